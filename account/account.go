@@ -0,0 +1,54 @@
+// Package account keeps players' identities across games: a stable display
+// name, a secret key to sign in with, and the results of the games they
+// played. Accounts are optional; players who don't sign in get a random name
+// for each connection, as before.
+package account
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeyBytes is how much randomness goes into an account key.
+const KeyBytes = 32
+
+// An Account is a player's identity across games. Games holds the IDs of the
+// game.Results it played in. Its key is only known to its owner; the store
+// keeps a hash of it.
+type Account struct {
+	ID      uuid.UUID   `json:"id"`
+	Name    string      `json:"name"`
+	Created time.Time   `json:"created"`
+	Games   []uuid.UUID `json:"games"`
+	KeyHash string      `json:"keyHash"`
+}
+
+// A Profile is the public part of an Account.
+type Profile struct {
+	ID      uuid.UUID   `json:"id"`
+	Name    string      `json:"name"`
+	Created time.Time   `json:"created"`
+	Games   []uuid.UUID `json:"games"`
+}
+
+func (a *Account) Profile() *Profile {
+	return &Profile{
+		ID:      a.ID,
+		Name:    a.Name,
+		Created: a.Created,
+		Games:   append([]uuid.UUID{}, a.Games...),
+	}
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *Account) checkKey(key string) bool {
+	return subtle.ConstantTimeCompare([]byte(a.KeyHash), []byte(hashKey(key))) == 1
+}
@@ -0,0 +1,45 @@
+package account
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type NotFoundError struct {
+	ID uuid.UUID
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("account: no account %s", e.ID)
+}
+
+func (e *NotFoundError) Code() string {
+	return "accountNotFound"
+}
+
+// An UnauthorizedError is returned for a wrong key. It doesn't say whether
+// the account exists.
+type UnauthorizedError struct {
+	ID uuid.UUID
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf("account: wrong key for account %s", e.ID)
+}
+
+func (e *UnauthorizedError) Code() string {
+	return "unauthorized"
+}
+
+type NameError struct {
+	Name string
+}
+
+func (e *NameError) Error() string {
+	return fmt.Sprintf("account: %q is not a valid name", e.Name)
+}
+
+func (e *NameError) Code() string {
+	return "invalidName"
+}
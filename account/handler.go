@@ -0,0 +1,121 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+
+	"github.com/google/uuid"
+)
+
+// A Handler serves a Store over HTTP, mounted at /accounts/:
+//
+//	POST  /accounts/              {"name": ...}  creates an account
+//	GET   /accounts/{id}                         returns its profile
+//	PATCH /accounts/{id}          {"name": ...}  renames it
+//	GET   /accounts/{id}/games                   returns its game results
+//
+// PATCH needs the account's key as a bearer token. Creating an account is
+// the only time its key is returned.
+type Handler struct {
+	Store *Store
+}
+
+// A Created is the response to creating an account.
+type Created struct {
+	*Profile
+	Key string `json:"key"`
+}
+
+type nameRequest struct {
+	Name string `json:"name"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/accounts"), "/")
+	if path == "" {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.create(w, r)
+		return
+	}
+
+	rawID, rest, _ := strings.Cut(path, "/")
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	switch {
+	case rest == "" && r.Method == http.MethodGet:
+		p, err := h.Store.Profile(id)
+		respond(w, p, err)
+	case rest == "" && r.Method == http.MethodPatch:
+		h.rename(w, r, id)
+	case rest == "games" && r.Method == http.MethodGet:
+		games, err := h.Store.Games(id)
+		respond(w, games, err)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	var req nameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p, key, err := h.Store.Create(req.Name)
+	if err != nil {
+		respond(w, nil, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(&Created{Profile: p, Key: key})
+}
+
+func (h *Handler) rename(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	var req nameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	key, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	p, err := h.Store.Rename(id, key, req.Name)
+	respond(w, p, err)
+}
+
+// respond writes v as JSON, or err as a server.ErrorMessage with a status to
+// match it.
+func respond(w http.ResponseWriter, v interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		var (
+			notFound     *NotFoundError
+			unauthorized *UnauthorizedError
+			badName      *NameError
+		)
+		switch {
+		case errors.As(err, &notFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.As(err, &unauthorized):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.As(err, &badName):
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			slog.Error("account: error handling request", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		v = server.NewErrorMessage("", err)
+	}
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,238 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/awoo-detat/werewolf/game"
+	"github.com/awoo-detat/werewolf/secret"
+
+	"github.com/google/uuid"
+)
+
+// MaxNameLength is the longest display name an account can have, in runes.
+const MaxNameLength = 32
+
+// A Store holds accounts and the results of the games they played. It is
+// safe for concurrent use. A Store opened with a path saves itself to that
+// file after every change; one opened without is kept in memory.
+type Store struct {
+	path     string
+	mu       sync.Mutex
+	accounts map[uuid.UUID]*Account
+	games    map[uuid.UUID]*game.Result
+}
+
+// storeFile is what a Store saves.
+type storeFile struct {
+	Accounts []*Account     `json:"accounts"`
+	Games    []*game.Result `json:"games"`
+}
+
+// Open loads the store saved at path, or starts a new one if there is no file
+// there yet. An empty path gives a store that is only kept in memory.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:     path,
+		accounts: make(map[uuid.UUID]*Account),
+		games:    make(map[uuid.UUID]*game.Result),
+	}
+	if path == "" {
+		return s, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f storeFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+	for _, a := range f.Accounts {
+		s.accounts[a.ID] = a
+	}
+	for _, r := range f.Games {
+		s.games[r.ID] = r
+	}
+	return s, nil
+}
+
+// save writes the store to its file, if it has one. The caller must hold
+// s.mu. The file is replaced in one step, so a crash can't leave half of it.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	f := storeFile{Accounts: []*Account{}, Games: []*game.Result{}}
+	for _, a := range s.accounts {
+		f.Accounts = append(f.Accounts, a)
+	}
+	for _, r := range s.games {
+		f.Games = append(f.Games, r)
+	}
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func validName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return "", &NameError{Name: name}
+	}
+	return name, nil
+}
+
+// Create makes a new account, returning it along with the key its owner signs
+// in with. The key can't be recovered later.
+func (s *Store) Create(name string) (*Profile, string, error) {
+	name, err := validName(name)
+	if err != nil {
+		return nil, "", err
+	}
+	key := secret.New(KeyBytes)
+	a := &Account{
+		ID:      uuid.New(),
+		Name:    name,
+		Created: time.Now(),
+		Games:   []uuid.UUID{},
+		KeyHash: hashKey(key),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[a.ID] = a
+	if err := s.save(); err != nil {
+		delete(s.accounts, a.ID)
+		return nil, "", err
+	}
+	return a.Profile(), key, nil
+}
+
+// Authenticate returns the account with the given ID if key is its key.
+func (s *Store) Authenticate(id uuid.UUID, key string) (*Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[id]
+	if !ok || !a.checkKey(key) {
+		return nil, &UnauthorizedError{ID: id}
+	}
+	return a.Profile(), nil
+}
+
+func (s *Store) Profile(id uuid.UUID) (*Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[id]
+	if !ok {
+		return nil, &NotFoundError{ID: id}
+	}
+	return a.Profile(), nil
+}
+
+// Rename changes an account's display name, if key is its key.
+func (s *Store) Rename(id uuid.UUID, key, name string) (*Profile, error) {
+	name, err := validName(name)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[id]
+	if !ok || !a.checkKey(key) {
+		return nil, &UnauthorizedError{ID: id}
+	}
+	old := a.Name
+	a.Name = name
+	if err := s.save(); err != nil {
+		a.Name = old
+		return nil, err
+	}
+	return a.Profile(), nil
+}
+
+// RecordGame keeps the result of a game and links it to the accounts of the
+// players in it.
+func (s *Store) RecordGame(r *game.Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	linked := map[*Account]int{}
+	for _, p := range r.Players {
+		if a, ok := s.accounts[p.AccountID]; ok {
+			linked[a] = len(a.Games)
+			a.Games = append(a.Games, r.ID)
+		}
+	}
+	s.games[r.ID] = r
+	if err := s.save(); err != nil {
+		// keep memory matching what is on disk
+		for a, n := range linked {
+			a.Games = a.Games[:n]
+		}
+		delete(s.games, r.ID)
+		return err
+	}
+	return nil
+}
+
+// Result returns the result with the given ID, if it was recorded.
+func (s *Store) Result(id uuid.UUID) (*game.Result, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.games[id]
+	return r, ok
+}
+
+// Results returns the result of every game recorded, oldest first.
+func (s *Store) Results() []*game.Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]*game.Result, 0, len(s.games))
+	for _, r := range s.games {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Finished.Before(results[j].Finished) })
+	return results
+}
+
+// Games returns the results of the games an account played, oldest first.
+func (s *Store) Games(id uuid.UUID) ([]*game.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.accounts[id]
+	if !ok {
+		return nil, &NotFoundError{ID: id}
+	}
+	results := []*game.Result{}
+	for _, resultID := range a.Games {
+		if r, ok := s.games[resultID]; ok {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,96 @@
+package account
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awoo-detat/werewolf/game"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "accounts.json")
+	s, err := Open(path)
+	require.NoError(t, err)
+
+	var nameErr *NameError
+	_, _, err = s.Create("   ")
+	assert.True(errors.As(err, &nameErr))
+
+	p, key, err := s.Create(" Alice ")
+	require.NoError(t, err)
+	assert.Equal("Alice", p.Name)
+	assert.NotEmpty(key)
+
+	var unauthorized *UnauthorizedError
+	_, err = s.Authenticate(p.ID, "wrong")
+	assert.True(errors.As(err, &unauthorized))
+	_, err = s.Authenticate(uuid.New(), key)
+	assert.True(errors.As(err, &unauthorized))
+	_, err = s.Rename(p.ID, "wrong", "Mallory")
+	assert.True(errors.As(err, &unauthorized))
+
+	_, err = s.Rename(p.ID, key, "Alicia")
+	assert.NoError(err)
+
+	played := &game.Result{ID: uuid.New(), GameID: uuid.New(), Players: []*game.PlayerResult{
+		{PlayerID: uuid.New(), AccountID: p.ID, Role: "Seer"},
+		{PlayerID: uuid.New()},
+	}}
+	assert.NoError(s.RecordGame(played))
+	assert.NoError(s.RecordGame(&game.Result{ID: uuid.New(), Players: []*game.PlayerResult{{PlayerID: uuid.New()}}}))
+
+	// everything survives a restart
+	s, err = Open(path)
+	require.NoError(t, err)
+	p, err = s.Authenticate(p.ID, key)
+	require.NoError(t, err)
+	assert.Equal("Alicia", p.Name)
+	assert.Equal([]uuid.UUID{played.ID}, p.Games)
+	games, err := s.Games(p.ID)
+	assert.NoError(err)
+	require.Len(t, games, 1)
+	assert.Equal("Seer", games[0].Players[0].Role)
+	assert.Len(s.Results(), 2)
+}
+
+func TestMemoryStore(t *testing.T) {
+	s, err := Open("")
+	require.NoError(t, err)
+	p, _, err := s.Create("Bob")
+	require.NoError(t, err)
+
+	var notFound *NotFoundError
+	_, err = s.Profile(uuid.New())
+	assert.True(t, errors.As(err, &notFound))
+	got, err := s.Profile(p.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, p, got)
+}
+
+func TestFailedSaveIsRolledBack(t *testing.T) {
+	assert := assert.New(t)
+	dir := filepath.Join(t.TempDir(), "store")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	s, err := Open(filepath.Join(dir, "accounts.json"))
+	require.NoError(t, err)
+	p, _, err := s.Create("Alice")
+	require.NoError(t, err)
+
+	// with the directory gone, nothing can be written
+	require.NoError(t, os.RemoveAll(dir))
+	played := &game.Result{ID: uuid.New(), Players: []*game.PlayerResult{{PlayerID: uuid.New(), AccountID: p.ID}}}
+	assert.Error(s.RecordGame(played))
+
+	p, err = s.Profile(p.ID)
+	require.NoError(t, err)
+	assert.Empty(p.Games)
+	_, ok := s.Result(played.ID)
+	assert.False(ok)
+}
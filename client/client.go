@@ -0,0 +1,237 @@
+// Package client is a Go SDK for the game protocol. It dials a server, sends
+// versioned requests, and turns everything the server says into typed Events.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/awoo-detat/werewolf/gamechannel"
+	protocol "github.com/awoo-detat/werewolf/gamechannel/client"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// EventBuffer is how many events are held for a slow reader before the
+// client stops reading from the server.
+const EventBuffer = 64
+
+// DialOptions says which seat to take when dialing. With neither Password nor
+// PlayerID set, a new game is created.
+type DialOptions struct {
+	// Password joins the game with that password.
+	Password string
+	// PlayerID reconnects to a seat that was dropped.
+	PlayerID uuid.UUID
+	// Token is the last session token sent for PlayerID's seat. It isn't
+	// needed if the seat belongs to the account being signed in to.
+	Token string
+	// AccountID and AccountKey sign in to an account on the server.
+	AccountID  uuid.UUID
+	AccountKey string
+}
+
+type Client struct {
+	conn     *websocket.Conn
+	events   chan Event
+	writeMu  sync.Mutex
+	requests atomic.Uint64
+	err      error
+}
+
+// Dial connects to the game websocket at rawURL, ie "ws://localhost:8080/ws".
+func Dial(ctx context.Context, rawURL string, opts DialOptions) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("client: bad url: %w", err)
+	}
+	query := u.Query()
+	if opts.Password != "" {
+		query.Set(gamechannel.PasswordParam, opts.Password)
+	}
+	if opts.PlayerID != uuid.Nil {
+		query.Set(gamechannel.PlayerParam, opts.PlayerID.String())
+		query.Set(gamechannel.TokenParam, opts.Token)
+	}
+	if opts.AccountID != uuid.Nil {
+		query.Set(gamechannel.AccountParam, opts.AccountID.String())
+		query.Set(gamechannel.KeyParam, opts.AccountKey)
+	}
+	u.RawQuery = query.Encode()
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("client: error dialing %s: %w", u, err)
+	}
+	c := &Client{
+		conn:   conn,
+		events: make(chan Event, EventBuffer),
+	}
+	go c.read()
+	return c, nil
+}
+
+// Create dials the server and creates a new game, led by this client.
+func Create(ctx context.Context, rawURL string) (*Client, error) {
+	return Dial(ctx, rawURL, DialOptions{})
+}
+
+// Join dials the server and joins the game with the given password.
+func Join(ctx context.Context, rawURL, password string) (*Client, error) {
+	return Dial(ctx, rawURL, DialOptions{Password: password})
+}
+
+// Reconnect dials the server and takes back the seat of the given player,
+// using the token from their last SessionTokenEvent.
+func Reconnect(ctx context.Context, rawURL string, id uuid.UUID, token string) (*Client, error) {
+	return Dial(ctx, rawURL, DialOptions{PlayerID: id, Token: token})
+}
+
+// Events returns the events sent by the server, in order. It is closed when
+// the connection ends, after which Err says why.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// Err returns the error that ended the connection, once Events is closed.
+func (c *Client) Err() error {
+	return c.err
+}
+
+func (c *Client) read() {
+	defer close(c.events)
+	for {
+		_, raw, err := c.conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				c.err = err
+			}
+			return
+		}
+		e, err := DecodeEvent(raw)
+		if err != nil {
+			slog.Warn("client: dropping message", "error", err)
+			continue
+		}
+		c.events <- e
+	}
+}
+
+// send writes a request to the server, returning its request ID so the
+// caller can match it to an AckEvent or ErrorEvent.
+func (c *Client) send(t protocol.MessageType, payload interface{}) (string, error) {
+	e := protocol.Envelope{
+		Version:   protocol.ProtocolVersion,
+		RequestID: strconv.FormatUint(c.requests.Add(1), 10),
+		Type:      t,
+	}
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return "", err
+		}
+		e.Payload = b
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.conn.WriteJSON(e); err != nil {
+		return "", fmt.Errorf("client: error sending %s: %w", t, err)
+	}
+	return e.RequestID, nil
+}
+
+func (c *Client) Awoo() (string, error) {
+	return c.send(protocol.Awoo, nil)
+}
+
+func (c *Client) SetName(name string) (string, error) {
+	return c.send(protocol.SetName, &protocol.SetNamePayload{PlayerName: name})
+}
+
+// SetRoleset chooses a roleset by name. Only the leader may do this.
+func (c *Client) SetRoleset(name string) (string, error) {
+	return c.send(protocol.SetRoleset, &protocol.SetRolesetPayload{Roleset: name})
+}
+
+// Start starts the game. Only the leader may do this.
+func (c *Client) Start() (string, error) {
+	return c.send(protocol.Start, nil)
+}
+
+func (c *Client) Vote(target uuid.UUID) (string, error) {
+	return c.send(protocol.Vote, &protocol.TargetPayload{Target: target})
+}
+
+func (c *Client) NightAction(target uuid.UUID) (string, error) {
+	return c.send(protocol.NightAction, &protocol.TargetPayload{Target: target})
+}
+
+// ResetGame puts a finished game back into setup for another round. Only the
+// leader may do this.
+func (c *Client) ResetGame() (string, error) {
+	return c.send(protocol.ResetGame, nil)
+}
+
+// TransferLeader makes another player the leader. Only the leader may do this.
+func (c *Client) TransferLeader(target uuid.UUID) (string, error) {
+	return c.send(protocol.TransferLeader, &protocol.TargetPayload{Target: target})
+}
+
+// Kick removes a player from a game that hasn't started, and keeps them from
+// rejoining. Only the leader may do this.
+func (c *Client) Kick(target uuid.UUID) (string, error) {
+	return c.send(protocol.Kick, &protocol.TargetPayload{Target: target})
+}
+
+// SetRevealMode chooses what everyone learns when a player dies: role, team,
+// villagerFlip or none. Only the leader may do this, before the game starts.
+func (c *Client) SetRevealMode(mode string) (string, error) {
+	return c.send(protocol.SetRevealMode, &protocol.SetRevealModePayload{Mode: mode})
+}
+
+// Shoot takes a revenge shot, once RevengeShotEvent says it's our turn.
+func (c *Client) Shoot(target uuid.UUID) (string, error) {
+	return c.send(protocol.Shoot, &protocol.TargetPayload{Target: target})
+}
+
+// ChooseLovers links two players as lovers, if we are Cupid. It must be done
+// on the first night, which lasts until we do.
+func (c *Client) ChooseLovers(first, second uuid.UUID) (string, error) {
+	return c.send(protocol.ChooseLovers, &protocol.LoversPayload{First: first, Second: second})
+}
+
+// UsePotion chooses our potion for tonight, if we are a Witch: "save" or
+// "poison" on target, or "none" with uuid.Nil to keep them for another night.
+// The night waits for this as long as we have a potion left.
+func (c *Client) UsePotion(potion string, target uuid.UUID) (string, error) {
+	return c.send(protocol.UsePotion, &protocol.PotionPayload{Potion: potion, Target: target})
+}
+
+// SetLynchRule chooses how many votes a lynch takes. dayLength, in seconds,
+// is how long days last under pluralityAtDeadline; 0 keeps the default. Only
+// the leader may do this, before the game starts.
+func (c *Client) SetLynchRule(rule string, dayLength int) (string, error) {
+	return c.send(protocol.SetLynchRule, &protocol.SetLynchRulePayload{Rule: rule, DayLength: dayLength})
+}
+
+// Quit leaves the game. The server will close the connection.
+func (c *Client) Quit() (string, error) {
+	return c.send(protocol.Quit, nil)
+}
+
+// Close drops the connection without quitting, so the seat can be taken back
+// with Reconnect.
+func (c *Client) Close() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return c.conn.Close()
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/account"
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/hub"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expect reads events from c until one of the given types arrives, failing
+// the test if none does in time.
+func expect(t *testing.T, c *Client, types ...server.MessageType) Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e, ok := <-c.Events():
+			if !ok {
+				// Err is only safe to read once Events is closed
+				require.FailNow(t, fmt.Sprintf("connection closed waiting for %v: %v", types, c.Err()))
+			}
+			for _, mt := range types {
+				if e.MessageType() == mt {
+					return e
+				}
+			}
+		case <-timeout:
+			require.FailNow(t, fmt.Sprintf("timed out waiting for %v", types))
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	assert := assert.New(t)
+	s := httptest.NewServer(hub.New())
+	defer s.Close()
+	url := "ws" + strings.TrimPrefix(s.URL, "http")
+	ctx := context.Background()
+
+	leader, err := Create(ctx, url)
+	require.NoError(t, err)
+	defer leader.Close()
+	leaderID := expect(t, leader, server.IDSet).(IDSetEvent).ID
+	assert.NotEqual(uuid.Nil, leaderID)
+	password := expect(t, leader, server.Password).(PasswordEvent).Password
+	assert.NotEmpty(password)
+
+	clients := []*Client{leader}
+	ids := []uuid.UUID{leaderID}
+	tokens := []string{""}
+	for i := 0; i < 4; i++ {
+		c, err := Join(ctx, url, password)
+		require.NoError(t, err)
+		defer c.Close()
+		clients = append(clients, c)
+		ids = append(ids, expect(t, c, server.IDSet).(IDSetEvent).ID)
+		tokens = append(tokens, expect(t, c, server.SessionToken).(SessionTokenEvent).Token)
+		assert.Equal(leaderID, expect(t, c, server.LeaderSet).(LeaderSetEvent).Leader.ID)
+	}
+
+	t.Run("bad password is rejected", func(t *testing.T) {
+		c, err := Join(ctx, url, "not a real password")
+		require.NoError(t, err)
+		e := expect(t, c, server.Error).(ErrorEvent)
+		assert.Equal("gameNotFound", e.Err.Code)
+		for range c.Events() {
+		}
+	})
+
+	t.Run("requests are acknowledged", func(t *testing.T) {
+		requestID, err := leader.SetRoleset("Vanilla Fiver")
+		require.NoError(t, err)
+		assert.Equal(requestID, expect(t, leader, server.Ack).(AckEvent).Ack.RequestID)
+		for _, c := range clients {
+			assert.Equal("Vanilla Fiver", expect(t, c, server.RolesetSelected).(RolesetSelectedEvent).Roleset.Name)
+		}
+	})
+
+	t.Run("game starts", func(t *testing.T) {
+		_, err := leader.Start()
+		require.NoError(t, err)
+		for _, c := range clients {
+			assert.NotEmpty(expect(t, c, server.RoleAssigned).(RoleAssignedEvent).Role.Name)
+			phase := expect(t, c, server.PhaseChanged).(PhaseChangedEvent).Phase
+			assert.Equal(server.Day, phase.Phase)
+			assert.Equal(1, phase.Count)
+		}
+	})
+
+	t.Run("errors reference the request", func(t *testing.T) {
+		requestID, err := clients[1].NightAction(uuid.New())
+		require.NoError(t, err)
+		e := expect(t, clients[1], server.Error).(ErrorEvent)
+		assert.Equal(requestID, e.Err.RequestID)
+		assert.Equal("unknownPlayer", e.Err.Code)
+	})
+
+	t.Run("reconnect needs the session token", func(t *testing.T) {
+		assert.NotEmpty(tokens[4])
+		c, err := Reconnect(ctx, url, ids[4], "not the token")
+		require.NoError(t, err)
+		assert.Equal("invalidToken", expect(t, c, server.Error).(ErrorEvent).Err.Code)
+		for range c.Events() {
+		}
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		clients[4].Close()
+		c, err := Reconnect(ctx, url, ids[4], tokens[4])
+		require.NoError(t, err)
+		clients[4] = c
+		rotated := expect(t, c, server.SessionToken).(SessionTokenEvent).Token
+		assert.NotEqual(tokens[4], rotated)
+		assert.NotEmpty(expect(t, c, server.RoleAssigned).(RoleAssignedEvent).Role.Name)
+		assert.Equal(1, expect(t, c, server.PhaseChanged).(PhaseChangedEvent).Phase.Count)
+
+		// the token it replaced can't be used again
+		stale, err := Reconnect(ctx, url, ids[4], tokens[4])
+		require.NoError(t, err)
+		assert.Equal("invalidToken", expect(t, stale, server.Error).(ErrorEvent).Err.Code)
+		for range stale.Events() {
+		}
+		tokens[4] = rotated
+	})
+
+	defer clients[4].Close()
+
+	t.Run("voting", func(t *testing.T) {
+		for _, c := range clients[1:4] {
+			_, err := c.Vote(ids[0])
+			require.NoError(t, err)
+		}
+		// the leader was either a villager, and it's now night, or the wolf,
+		// and the game is over
+		for _, c := range clients {
+			switch e := expect(t, c, server.PhaseChanged, server.GameOver).(type) {
+			case PhaseChangedEvent:
+				assert.Equal(server.GamePhase(server.Night), e.Phase.Phase)
+			case GameOverEvent:
+				assert.Len(e.GameOver.Roles, 5)
+			}
+		}
+	})
+}
+
+func TestKick(t *testing.T) {
+	store, err := account.Open("")
+	require.NoError(t, err)
+	h := hub.New()
+	h.Accounts = store
+	s := httptest.NewServer(h)
+	defer s.Close()
+	url := "ws" + strings.TrimPrefix(s.URL, "http")
+	ctx := context.Background()
+	mallory, key, err := store.Create("Mallory")
+	require.NoError(t, err)
+
+	leader, err := Create(ctx, url)
+	require.NoError(t, err)
+	defer leader.Close()
+	expect(t, leader, server.IDSet)
+	password := expect(t, leader, server.Password).(PasswordEvent).Password
+
+	signedIn := DialOptions{Password: password, AccountID: mallory.ID, AccountKey: key}
+	kicked, err := Dial(ctx, url, signedIn)
+	require.NoError(t, err)
+	defer kicked.Close()
+	kickedID := expect(t, kicked, server.IDSet).(IDSetEvent).ID
+
+	_, err = leader.Kick(kickedID)
+	require.NoError(t, err)
+	expect(t, kicked, server.Kicked)
+	for range kicked.Events() {
+	}
+
+	// the password they knew is no good any more
+	stale, err := Join(ctx, url, password)
+	require.NoError(t, err)
+	assert.Equal(t, "gameNotFound", expect(t, stale, server.Error).(ErrorEvent).Err.Code)
+	password = expect(t, leader, server.Password).(PasswordEvent).Password
+
+	// even with the new one, their account is banned, but not everyone else
+	// on the same address
+	signedIn.Password = password
+	again, err := Dial(ctx, url, signedIn)
+	require.NoError(t, err)
+	assert.Equal(t, "banned", expect(t, again, server.Error).(ErrorEvent).Err.Code)
+	neighbour, err := Join(ctx, url, password)
+	require.NoError(t, err)
+	defer neighbour.Close()
+	expect(t, neighbour, server.LeaderSet)
+}
+
+func TestAccounts(t *testing.T) {
+	assert := assert.New(t)
+	store, err := account.Open("")
+	require.NoError(t, err)
+	h := hub.New()
+	h.Accounts = store
+	s := httptest.NewServer(h)
+	defer s.Close()
+	url := "ws" + strings.TrimPrefix(s.URL, "http")
+	ctx := context.Background()
+
+	alice, key, err := store.Create("Alice")
+	require.NoError(t, err)
+	mallory, malloryKey, err := store.Create("Mallory")
+	require.NoError(t, err)
+
+	bad, err := Dial(ctx, url, DialOptions{AccountID: alice.ID, AccountKey: malloryKey})
+	require.NoError(t, err)
+	assert.Equal("unauthorized", expect(t, bad, server.Error).(ErrorEvent).Err.Code)
+	for range bad.Events() {
+	}
+
+	c, err := Dial(ctx, url, DialOptions{AccountID: alice.ID, AccountKey: key})
+	require.NoError(t, err)
+	id := expect(t, c, server.IDSet).(IDSetEvent).ID
+	// the random name comes first, then the account's
+	expect(t, c, server.NameSet)
+	assert.Equal("Alice", expect(t, c, server.NameSet).(NameSetEvent).Name)
+	expect(t, c, server.Password)
+	c.Close()
+
+	// another account can't take the seat, but Alice can from anywhere
+	stolen, err := Dial(ctx, url, DialOptions{PlayerID: id, AccountID: mallory.ID, AccountKey: malloryKey})
+	require.NoError(t, err)
+	assert.Equal("invalidToken", expect(t, stolen, server.Error).(ErrorEvent).Err.Code)
+	for range stolen.Events() {
+	}
+	c, err = Dial(ctx, url, DialOptions{PlayerID: id, AccountID: alice.ID, AccountKey: key})
+	require.NoError(t, err)
+	defer c.Close()
+	assert.NotEmpty(expect(t, c, server.SessionToken).(SessionTokenEvent).Token)
+	assert.Equal("Alice", expect(t, c, server.LeaderSet).(LeaderSetEvent).Leader.Name)
+}
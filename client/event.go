@@ -0,0 +1,239 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/role/roleset"
+	"github.com/awoo-detat/werewolf/tally"
+
+	"github.com/google/uuid"
+)
+
+// An Event is a decoded message from the server. Each server.MessageType has
+// its own Event type; switch on the concrete type to handle them.
+type Event interface {
+	MessageType() server.MessageType
+}
+
+type AwooEvent struct{ Message string }
+type IDSetEvent struct{ ID uuid.UUID }
+type NameSetEvent struct{ Name string }
+type PlayerJoinEvent struct{ Player *player.Player }
+type PlayerLeaveEvent struct{ Player *player.Player }
+type PlayerListEvent struct{ Players []*player.Player }
+type RolesetListEvent struct{ Rolesets roleset.RolesetMap }
+type RolesetSelectedEvent struct{ Roleset *roleset.Roleset }
+
+// A LeaderSetEvent's Leader is nil if the game has no leader.
+type LeaderSetEvent struct{ Leader *player.Player }
+type PasswordEvent struct{ Password string }
+type TallyChangedEvent struct{ Tally *tally.Tally }
+type RoleAssignedEvent struct{ Role *role.Role }
+type PhaseChangedEvent struct{ Phase *server.Phase }
+type ViewEvent struct{ View *player.View }
+type PlayerKilledEvent struct{}
+type GameOverEvent struct{ GameOver *server.GameOverMessage }
+type ErrorEvent struct{ Err *server.ErrorMessage }
+type AckEvent struct{ Ack *server.Acknowledgement }
+
+// A GameResetEvent means the game is back in setup for another round. Roles,
+// views, the tally and the result of the last game no longer apply.
+type GameResetEvent struct{}
+
+// A KickedEvent means the leader removed this client from the game. The
+// server closes the connection after sending it.
+type KickedEvent struct{}
+
+// A PlayerSubstitutedEvent means someone new has taken over Player's seat.
+// If this client is the substitute, it is sent IDSet and NameSet first.
+type PlayerSubstitutedEvent struct{ Player *player.Player }
+
+// A SessionTokenEvent carries the secret needed to reconnect to this seat.
+// Each token works once; a new one is sent after every reconnect.
+type SessionTokenEvent struct{ Token string }
+
+// A TimelineEvent follows GameOver with everything that happened in the game:
+// votes, night actions and what they saw, and deaths.
+type TimelineEvent struct{ Events []*server.TimelineEvent }
+
+// A VoteHistoryEvent has the vote log of every day that has ended so far.
+// Today's log is on the tally.
+type VoteHistoryEvent struct{ Days []*tally.Day }
+
+// A LynchRuleSetEvent says how many votes a lynch will take, and how long
+// days last if the rule has a deadline.
+type LynchRuleSetEvent struct{ LynchRule *server.LynchRule }
+
+// A RevengeShotEvent says a hunter has died and the game is waiting for them
+// to shoot. If they are us, Shoot before the deadline.
+type RevengeShotEvent struct{ Prompt *server.RevengeShotPrompt }
+
+// A RevealModeSetEvent says what will be revealed about players who die.
+type RevealModeSetEvent struct{ Mode string }
+
+// An UnknownEvent is a message of a type this package doesn't know about,
+// most likely from a newer server.
+type UnknownEvent struct {
+	Type    server.MessageType
+	Payload json.RawMessage
+}
+
+func (AwooEvent) MessageType() server.MessageType              { return server.Awoo }
+func (IDSetEvent) MessageType() server.MessageType             { return server.IDSet }
+func (NameSetEvent) MessageType() server.MessageType           { return server.NameSet }
+func (PlayerJoinEvent) MessageType() server.MessageType        { return server.PlayerJoin }
+func (PlayerLeaveEvent) MessageType() server.MessageType       { return server.PlayerLeave }
+func (PlayerListEvent) MessageType() server.MessageType        { return server.AlivePlayerList }
+func (RolesetListEvent) MessageType() server.MessageType       { return server.RolesetList }
+func (RolesetSelectedEvent) MessageType() server.MessageType   { return server.RolesetSelected }
+func (LeaderSetEvent) MessageType() server.MessageType         { return server.LeaderSet }
+func (PasswordEvent) MessageType() server.MessageType          { return server.Password }
+func (TallyChangedEvent) MessageType() server.MessageType      { return server.TallyChanged }
+func (RoleAssignedEvent) MessageType() server.MessageType      { return server.RoleAssigned }
+func (PhaseChangedEvent) MessageType() server.MessageType      { return server.PhaseChanged }
+func (ViewEvent) MessageType() server.MessageType              { return server.View }
+func (PlayerKilledEvent) MessageType() server.MessageType      { return server.PlayerKilled }
+func (GameOverEvent) MessageType() server.MessageType          { return server.GameOver }
+func (ErrorEvent) MessageType() server.MessageType             { return server.Error }
+func (AckEvent) MessageType() server.MessageType               { return server.Ack }
+func (GameResetEvent) MessageType() server.MessageType         { return server.GameReset }
+func (KickedEvent) MessageType() server.MessageType            { return server.Kicked }
+func (PlayerSubstitutedEvent) MessageType() server.MessageType { return server.PlayerSubstituted }
+func (SessionTokenEvent) MessageType() server.MessageType      { return server.SessionToken }
+func (TimelineEvent) MessageType() server.MessageType          { return server.Timeline }
+func (VoteHistoryEvent) MessageType() server.MessageType       { return server.VoteHistory }
+func (LynchRuleSetEvent) MessageType() server.MessageType      { return server.LynchRuleSet }
+func (RevengeShotEvent) MessageType() server.MessageType       { return server.RevengeShot }
+func (RevealModeSetEvent) MessageType() server.MessageType     { return server.RevealModeSet }
+func (e UnknownEvent) MessageType() server.MessageType         { return e.Type }
+
+// rawMessage is a server.Message whose payload hasn't been decoded yet.
+type rawMessage struct {
+	Type    server.MessageType `json:"messageType"`
+	Payload json.RawMessage    `json:"payload"`
+}
+
+// DecodeEvent turns a raw server message into its Event.
+func DecodeEvent(raw []byte) (Event, error) {
+	var m rawMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("client: error decoding server message: %w", err)
+	}
+
+	var e Event
+	var err error
+	switch m.Type {
+	case server.Awoo:
+		ev := AwooEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Message)
+		e = ev
+	case server.IDSet:
+		ev := IDSetEvent{}
+		err = json.Unmarshal(m.Payload, &ev.ID)
+		e = ev
+	case server.NameSet:
+		ev := NameSetEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Name)
+		e = ev
+	case server.PlayerJoin:
+		ev := PlayerJoinEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Player)
+		e = ev
+	case server.PlayerLeave:
+		ev := PlayerLeaveEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Player)
+		e = ev
+	case server.AlivePlayerList:
+		ev := PlayerListEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Players)
+		e = ev
+	case server.RolesetList:
+		ev := RolesetListEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Rolesets)
+		e = ev
+	case server.RolesetSelected:
+		ev := RolesetSelectedEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Roleset)
+		e = ev
+	case server.LeaderSet:
+		ev := LeaderSetEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Leader)
+		e = ev
+	case server.Password:
+		ev := PasswordEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Password)
+		e = ev
+	case server.TallyChanged:
+		ev := TallyChangedEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Tally)
+		e = ev
+	case server.RoleAssigned:
+		ev := RoleAssignedEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Role)
+		e = ev
+	case server.PhaseChanged:
+		ev := PhaseChangedEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Phase)
+		e = ev
+	case server.View:
+		ev := ViewEvent{}
+		err = json.Unmarshal(m.Payload, &ev.View)
+		e = ev
+	case server.PlayerKilled:
+		e = PlayerKilledEvent{}
+	case server.GameOver:
+		ev := GameOverEvent{}
+		err = json.Unmarshal(m.Payload, &ev.GameOver)
+		e = ev
+	case server.Error:
+		ev := ErrorEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Err)
+		e = ev
+	case server.Ack:
+		ev := AckEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Ack)
+		e = ev
+	case server.GameReset:
+		e = GameResetEvent{}
+	case server.Kicked:
+		e = KickedEvent{}
+	case server.PlayerSubstituted:
+		ev := PlayerSubstitutedEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Player)
+		e = ev
+	case server.SessionToken:
+		ev := SessionTokenEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Token)
+		e = ev
+	case server.Timeline:
+		ev := TimelineEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Events)
+		e = ev
+	case server.VoteHistory:
+		ev := VoteHistoryEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Days)
+		e = ev
+	case server.LynchRuleSet:
+		ev := LynchRuleSetEvent{}
+		err = json.Unmarshal(m.Payload, &ev.LynchRule)
+		e = ev
+	case server.RevengeShot:
+		ev := RevengeShotEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Prompt)
+		e = ev
+	case server.RevealModeSet:
+		ev := RevealModeSetEvent{}
+		err = json.Unmarshal(m.Payload, &ev.Mode)
+		e = ev
+	default:
+		e = UnknownEvent{Type: m.Type, Payload: m.Payload}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("client: error decoding %s payload: %w", m.Type, err)
+	}
+	return e, nil
+}
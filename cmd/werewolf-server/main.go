@@ -0,0 +1,64 @@
+// Command werewolf-server serves games over a websocket.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/awoo-detat/werewolf/account"
+	"github.com/awoo-detat/werewolf/history"
+	"github.com/awoo-detat/werewolf/hub"
+	"github.com/awoo-detat/werewolf/stats"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	anyOrigin := flag.Bool("any-origin", false, "accept websocket connections from any origin")
+	accounts := flag.String("accounts", "", "file to keep player accounts and game results in; if empty, there are no accounts and results are only kept in memory")
+	flag.Parse()
+
+	h := hub.New()
+	if *anyOrigin {
+		h.Upgrader.CheckOrigin = func(*http.Request) bool { return true }
+	}
+	// results are kept in the account store, which lasts across restarts, or
+	// failing that in memory
+	var results history.Source
+	if *accounts != "" {
+		store, err := account.Open(*accounts)
+		if err != nil {
+			slog.Error("error opening accounts", "file", *accounts, "error", err)
+			os.Exit(1)
+		}
+		h.Accounts = store
+		http.Handle("/accounts/", &account.Handler{Store: store})
+		results = store
+	} else {
+		h.History = &history.Memory{}
+		results = h.History
+	}
+	http.Handle("/stats", &stats.Handler{Source: results})
+	http.Handle("/games/", &history.Handler{Source: results})
+	http.Handle("/ws", h)
+	srv := &http.Server{Addr: *addr}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		slog.Info("shutting down")
+		h.Close()
+		srv.Shutdown(context.Background())
+	}()
+
+	slog.Info("listening", "addr", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
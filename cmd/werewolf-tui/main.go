@@ -0,0 +1,199 @@
+// Command werewolf-tui plays a game from the terminal. Type "help" once
+// connected to see the commands.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/awoo-detat/werewolf/client"
+
+	"github.com/google/uuid"
+)
+
+const help = `commands:
+  name <name>       change your name
+  roleset <number>  choose a roleset (leader only)
+  start             start the game (leader only)
+  reset             play again once the game is over (leader only)
+  leader <number>   make another player the leader (leader only)
+  kick <number>     remove a player before the game starts (leader only)
+  lynch <rule> [s]  half, strictMajority, supermajority or
+                    pluralityAtDeadline with a day length (leader only)
+  reveal <mode>     what a death reveals: role, team, villagerFlip or
+                    none (leader only)
+  vote <number>     vote for a player during the day
+  act <number>      choose your night action's target
+  shoot <number>    take your revenge shot, if you're a hunter who died
+  love <n> <m>      choose two players to fall in love, if you're Cupid
+  potion <p> [n]    save or poison a player tonight, or none, if you're
+                    a Witch
+  awoo              awoo
+  quit              leave the game`
+
+func main() {
+	url := flag.String("url", "ws://localhost:8080/ws", "game server websocket")
+	password := flag.String("password", "", "password of the game to join; leave empty to create one")
+	id := flag.String("id", "", "player ID to reconnect as")
+	token := flag.String("token", "", "session token for reconnecting as -id")
+	accountID := flag.String("account", "", "account ID to sign in with")
+	key := flag.String("key", "", "key of the -account")
+	flag.Parse()
+
+	opts := client.DialOptions{Password: *password}
+	if *id != "" {
+		playerID, err := uuid.Parse(*id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bad player ID: %v\n", err)
+			os.Exit(2)
+		}
+		opts.PlayerID = playerID
+		opts.Token = *token
+	}
+	if *accountID != "" {
+		id, err := uuid.Parse(*accountID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bad account ID: %v\n", err)
+			os.Exit(2)
+		}
+		opts.AccountID = id
+		opts.AccountKey = *key
+	}
+
+	c, err := client.Dial(context.Background(), *url, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer c.Close()
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	s := newState()
+	s.logf("type help for commands")
+	for {
+		s.render(os.Stdout)
+		select {
+		case e, ok := <-c.Events():
+			if !ok {
+				fmt.Println("\ndisconnected:", c.Err())
+				if s.id != uuid.Nil {
+					fmt.Printf("reconnect with -id %s -token %s\n", s.id, s.token)
+				}
+				return
+			}
+			s.apply(e)
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			if quit := s.run(c, line); quit {
+				return
+			}
+		}
+	}
+}
+
+// run carries out a command typed by the player, returning whether to exit.
+func (s *state) run(c *client.Client, line string) bool {
+	command, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+
+	var err error
+	switch command {
+	case "":
+	case "help":
+		s.logf("%s", help)
+	case "name":
+		_, err = c.SetName(arg)
+	case "roleset":
+		var n int
+		if n, err = s.pick(arg, len(s.rolesets)); err == nil {
+			_, err = c.SetRoleset(s.rolesets[n].Name)
+		}
+	case "start":
+		_, err = c.Start()
+	case "reset":
+		_, err = c.ResetGame()
+	case "lynch":
+		rule, seconds, _ := strings.Cut(arg, " ")
+		dayLength := 0
+		if seconds != "" {
+			dayLength, err = strconv.Atoi(seconds)
+		}
+		if err == nil {
+			_, err = c.SetLynchRule(rule, dayLength)
+		}
+	case "reveal":
+		_, err = c.SetRevealMode(arg)
+	case "love":
+		first, second, _ := strings.Cut(arg, " ")
+		var a, b int
+		if a, err = s.pick(first, len(s.players)); err == nil {
+			if b, err = s.pick(second, len(s.players)); err == nil {
+				_, err = c.ChooseLovers(s.players[a].ID, s.players[b].ID)
+			}
+		}
+	case "potion":
+		potion, number, _ := strings.Cut(arg, " ")
+		target := uuid.Nil
+		if number != "" {
+			var n int
+			if n, err = s.pick(number, len(s.players)); err == nil {
+				target = s.players[n].ID
+			}
+		}
+		if err == nil {
+			_, err = c.UsePotion(potion, target)
+		}
+	case "vote", "act", "shoot", "leader", "kick":
+		var n int
+		if n, err = s.pick(arg, len(s.players)); err == nil {
+			target := s.players[n].ID
+			switch command {
+			case "vote":
+				_, err = c.Vote(target)
+			case "act":
+				_, err = c.NightAction(target)
+			case "shoot":
+				_, err = c.Shoot(target)
+			case "leader":
+				_, err = c.TransferLeader(target)
+			case "kick":
+				_, err = c.Kick(target)
+			}
+		}
+	case "awoo":
+		_, err = c.Awoo()
+	case "quit":
+		c.Quit()
+		return true
+	default:
+		err = fmt.Errorf("unknown command %q", command)
+	}
+	if err != nil {
+		s.logf("%v", err)
+	}
+	return false
+}
+
+// pick turns a 1-based number typed by the player into an index.
+func (s *state) pick(arg string, count int) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > count {
+		return 0, fmt.Errorf("pick a number from 1 to %d", count)
+	}
+	return n - 1, nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/tally"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+func (s *state) render(w io.Writer) {
+	fmt.Fprint(w, clearScreen)
+	fmt.Fprintf(w, "== werewolf == you are %s", s.name)
+	if s.isLeader() {
+		fmt.Fprint(w, " (leader)")
+	}
+	if s.dead {
+		fmt.Fprint(w, " [dead]")
+	}
+	fmt.Fprintln(w)
+	if s.password != "" {
+		fmt.Fprintf(w, "password: %s\n", s.password)
+	}
+	if s.phase != nil {
+		fmt.Fprintf(w, "it is %s %d\n", s.phase.Phase, (s.phase.Count+1)/2)
+	}
+
+	fmt.Fprintln(w, "\nplayers:")
+	for i, p := range s.players {
+		fmt.Fprintf(w, "  %2d. %s\n", i+1, s.nameOf(p))
+	}
+
+	if s.lynch != nil && s.phase == nil {
+		fmt.Fprintf(w, "\nlynch rule: %s", s.lynch.Rule)
+		if s.lynch.DayLength > 0 {
+			fmt.Fprintf(w, ", %ds days", s.lynch.DayLength)
+		}
+		fmt.Fprintln(w)
+	}
+	if s.reveal != "" && s.phase == nil {
+		fmt.Fprintf(w, "deaths reveal: %s\n", s.reveal)
+	}
+
+	if s.roleset != nil {
+		fmt.Fprintf(w, "\nroleset: %s - %s\n", s.roleset.Name, s.roleset.Description)
+	} else if s.isLeader() && len(s.rolesets) > 0 {
+		fmt.Fprintln(w, "\nrolesets:")
+		for i, rs := range s.rolesets {
+			fmt.Fprintf(w, "  %2d. %s (%d players)\n", i+1, rs.Name, len(rs.Roles))
+		}
+	}
+
+	if s.role != nil {
+		fmt.Fprintf(w, "\nyour role: %s (%s)\n  %s\n", s.role.Name, s.role.Team, s.role.Description)
+	}
+
+	if s.tally != nil {
+		fmt.Fprint(w, "\ntally")
+		if s.tally.VotesNeeded > 0 {
+			fmt.Fprintf(w, " (%d to lynch)", s.tally.VotesNeeded)
+		}
+		if s.tally.Deadline != nil {
+			fmt.Fprintf(w, " (ends in %s)", time.Until(*s.tally.Deadline).Round(time.Second))
+		}
+		fmt.Fprintln(w, ":")
+		for _, item := range s.tally.List {
+			if len(item.Votes) == 0 {
+				continue
+			}
+			voters := []string{}
+			for _, v := range item.Votes {
+				voters = append(voters, s.nameOf(v.Voter))
+			}
+			fmt.Fprintf(w, "  %-20s %d (%s)\n", s.nameOf(item.Player), len(item.Votes), strings.Join(voters, ", "))
+		}
+		log := s.tally.Log
+		if len(log) > 5 {
+			log = log[len(log)-5:]
+		}
+		for _, c := range log {
+			fmt.Fprintf(w, "  %s\n", s.describeChange(c))
+		}
+	}
+
+	for _, day := range s.history {
+		fmt.Fprintf(w, "\n%s votes:\n", when(day.Phase))
+		for _, c := range day.Log {
+			fmt.Fprintf(w, "  %s\n", s.describeChange(c))
+		}
+	}
+
+	if len(s.views) > 0 {
+		fmt.Fprintln(w, "\nwhat you know:")
+		for _, v := range s.views {
+			fmt.Fprintf(w, "  %s\n", s.describe(v))
+		}
+	}
+
+	if s.gameOver != nil {
+		fmt.Fprintf(w, "\nGAME OVER: %s wins\n", s.gameOver.Winner)
+		for _, p := range s.gameOver.Roles {
+			notes := ""
+			if p.Tinker {
+				notes += " (Tinker)"
+			}
+			if p.Lover {
+				notes += " (Lover)"
+			}
+			if slices.Contains(s.gameOver.Winners, p.ID) {
+				notes += " - won"
+			}
+			fmt.Fprintf(w, "  %-20s %s%s\n", p.Name, p.Role, notes)
+		}
+		for _, e := range s.timeline {
+			fmt.Fprintf(w, "  %s\n", s.describeEvent(e))
+		}
+	}
+
+	if len(s.log) > 0 {
+		fmt.Fprintln(w)
+		for _, l := range s.log {
+			fmt.Fprintf(w, "* %s\n", l)
+		}
+	}
+	fmt.Fprint(w, "\n> ")
+}
+
+func when(phase int) string {
+	if phase%2 == 1 {
+		return fmt.Sprintf("%s %d", server.Day, (phase+1)/2)
+	}
+	return fmt.Sprintf("%s %d", server.Night, phase/2)
+}
+
+func (s *state) describe(v *player.View) string {
+	when := when(v.GamePhase)
+	name := s.nameOf(v.Player)
+	if v.Role != nil {
+		return fmt.Sprintf("%s: %s was the %s", when, name, v.Role.Name)
+	}
+	if v.Team != nil {
+		return fmt.Sprintf("%s: %s was %s", when, name, v.Team)
+	}
+	if v.Attribute == role.LoverAttribute {
+		return fmt.Sprintf("%s: you are in love with %s", when, name)
+	}
+	if v.Hit {
+		return fmt.Sprintf("%s: %s is %s", when, name, v.Attribute)
+	}
+	return fmt.Sprintf("%s: %s is not %s", when, name, v.Attribute)
+}
+
+func (s *state) describeEvent(e *server.TimelineEvent) string {
+	from, to := s.names[e.From], s.names[e.To]
+	switch e.Type {
+	case server.VoteCast:
+		return fmt.Sprintf("%s %s: %s voted for %s", when(e.Phase), e.Time.Format(time.TimeOnly), from, to)
+	case server.VoteWithdrawn:
+		return fmt.Sprintf("%s %s: %s withdrew their vote", when(e.Phase), e.Time.Format(time.TimeOnly), from)
+	case server.NightAction:
+		return fmt.Sprintf("%s: %s chose %s", when(e.Phase), from, to)
+	case server.ViewResult:
+		result := "is"
+		if !e.Hit {
+			result = "is not"
+		}
+		lie := ""
+		if e.False {
+			lie = " (false)"
+		}
+		return fmt.Sprintf("%s: %s saw %s %s %s%s", when(e.Phase), from, to, result, e.Attribute, lie)
+	case server.TinkerAssigned:
+		return fmt.Sprintf("%s was a Tinker", to)
+	case server.LoversLinked:
+		return fmt.Sprintf("%s: %s and %s fell in love", when(e.Phase), from, to)
+	case server.Roleblocked:
+		return fmt.Sprintf("%s: %s blocked %s", when(e.Phase), from, to)
+	case server.WitchSaved:
+		return fmt.Sprintf("%s: %s saved %s", when(e.Phase), from, to)
+	case server.WitchPoisoned:
+		return fmt.Sprintf("%s: %s poisoned %s", when(e.Phase), from, to)
+	case server.HunterShot:
+		return fmt.Sprintf("%s: %s shot %s", when(e.Phase), from, to)
+	case server.Death:
+		return fmt.Sprintf("%s: %s died (%s)", when(e.Phase), to, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", when(e.Phase), e.Type)
+}
+
+func (s *state) describeChange(c *tally.Change) string {
+	at := c.Timestamp.Format(time.TimeOnly)
+	switch {
+	case c.To == nil:
+		return fmt.Sprintf("%s %s unvoted %s", at, s.nameOf(c.Voter), s.nameOf(c.From))
+	case c.From == nil:
+		return fmt.Sprintf("%s %s voted %s", at, s.nameOf(c.Voter), s.nameOf(c.To))
+	}
+	return fmt.Sprintf("%s %s switched from %s to %s", at, s.nameOf(c.Voter), s.nameOf(c.From), s.nameOf(c.To))
+}
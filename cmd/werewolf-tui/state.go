@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/awoo-detat/werewolf/client"
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/role/roleset"
+	"github.com/awoo-detat/werewolf/tally"
+
+	"github.com/google/uuid"
+)
+
+// state is everything the server has told us, folded together from events.
+type state struct {
+	id       uuid.UUID
+	token    string
+	name     string
+	leader   *player.Player
+	password string
+	players  []*player.Player
+	names    map[uuid.UUID]string
+	rolesets []*roleset.Roleset
+	roleset  *roleset.Roleset
+	role     *role.Role
+	dead     bool
+	phase    *server.Phase
+	tally    *tally.Tally
+	lynch    *server.LynchRule
+	reveal   string
+	views    []*player.View
+	gameOver *server.GameOverMessage
+	timeline []*server.TimelineEvent
+	history  []*tally.Day
+	log      []string
+}
+
+func newState() *state {
+	return &state{names: make(map[uuid.UUID]string)}
+}
+
+func (s *state) isLeader() bool {
+	return s.leader != nil && s.leader.ID == s.id
+}
+
+// nameOf looks up the most recent name we've seen for a player.
+func (s *state) nameOf(p *player.Player) string {
+	if p == nil {
+		return "nobody"
+	}
+	if name, ok := s.names[p.ID]; ok {
+		return name
+	}
+	return p.Name
+}
+
+func (s *state) logf(format string, args ...interface{}) {
+	s.log = append(s.log, fmt.Sprintf(format, args...))
+	if len(s.log) > 5 {
+		s.log = s.log[len(s.log)-5:]
+	}
+}
+
+func (s *state) remember(players ...*player.Player) {
+	for _, p := range players {
+		if p != nil {
+			s.names[p.ID] = p.Name
+		}
+	}
+}
+
+func (s *state) apply(e client.Event) {
+	switch e := e.(type) {
+	case client.AwooEvent:
+		s.logf("%s", e.Message)
+	case client.IDSetEvent:
+		s.id = e.ID
+	case client.SessionTokenEvent:
+		s.token = e.Token
+	case client.NameSetEvent:
+		s.name = e.Name
+	case client.PlayerJoinEvent:
+		s.remember(e.Player)
+		s.logf("%s joined", s.nameOf(e.Player))
+	case client.PlayerLeaveEvent:
+		s.logf("%s left", s.nameOf(e.Player))
+	case client.PlayerListEvent:
+		s.remember(e.Players...)
+		s.players = e.Players
+		sort.Slice(s.players, func(i, j int) bool { return s.players[i].Name < s.players[j].Name })
+		for _, p := range s.players {
+			if p.ID == s.id {
+				s.name = p.Name
+			}
+		}
+	case client.RolesetListEvent:
+		s.rolesets = []*roleset.Roleset{}
+		for _, rs := range e.Rolesets {
+			s.rolesets = append(s.rolesets, rs)
+		}
+		sort.Slice(s.rolesets, func(i, j int) bool {
+			if len(s.rolesets[i].Roles) != len(s.rolesets[j].Roles) {
+				return len(s.rolesets[i].Roles) < len(s.rolesets[j].Roles)
+			}
+			return s.rolesets[i].Name < s.rolesets[j].Name
+		})
+	case client.RolesetSelectedEvent:
+		s.roleset = e.Roleset
+		s.logf("roleset is now %s", e.Roleset.Name)
+	case client.LeaderSetEvent:
+		s.leader = e.Leader
+		s.remember(e.Leader)
+		s.logf("%s is the leader", s.nameOf(e.Leader))
+	case client.PasswordEvent:
+		s.password = e.Password
+	case client.RevengeShotEvent:
+		if e.Prompt.Hunter == s.id {
+			s.logf("take your revenge shot within %s: shoot <number>", time.Until(e.Prompt.Deadline).Round(time.Second))
+		} else {
+			s.logf("%s is taking their revenge shot", s.names[e.Prompt.Hunter])
+		}
+	case client.RevealModeSetEvent:
+		s.reveal = e.Mode
+		s.logf("deaths now reveal: %s", e.Mode)
+	case client.LynchRuleSetEvent:
+		s.lynch = e.LynchRule
+		s.logf("lynch rule is now %s", e.LynchRule.Rule)
+	case client.TallyChangedEvent:
+		s.tally = e.Tally
+	case client.RoleAssignedEvent:
+		s.role = e.Role
+		s.gameOver = nil
+		s.timeline = nil
+	case client.PhaseChangedEvent:
+		s.phase = e.Phase
+		if e.Phase.Phase == server.Night {
+			s.tally = nil
+		}
+	case client.ViewEvent:
+		s.views = append(s.views, e.View)
+	case client.PlayerKilledEvent:
+		s.dead = true
+		s.logf("you have been killed")
+	case client.GameOverEvent:
+		s.gameOver = e.GameOver
+		for _, p := range e.GameOver.Roles {
+			s.names[p.ID] = p.Name
+		}
+	case client.TimelineEvent:
+		s.timeline = e.Events
+	case client.VoteHistoryEvent:
+		s.history = e.Days
+	case client.PlayerSubstitutedEvent:
+		s.logf("someone new is playing as %s", s.nameOf(e.Player))
+	case client.KickedEvent:
+		s.logf("you have been kicked")
+	case client.GameResetEvent:
+		s.role = nil
+		s.dead = false
+		s.phase = nil
+		s.tally = nil
+		s.views = nil
+		s.gameOver = nil
+		s.timeline = nil
+		s.history = nil
+		s.logf("the game has been reset")
+	case client.ErrorEvent:
+		s.logf("error: %s", e.Err.Message)
+	}
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel"
+	"github.com/awoo-detat/werewolf/player"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConcurrentUse is meant to be run with -race. Players act through the
+// game channel while other goroutines call into the game and read from it.
+func TestConcurrentUse(t *testing.T) {
+	assert := assert.New(t)
+	players := []*player.Player{}
+	for i := 0; i < 5; i++ {
+		players = append(players, player.NewPlayer(player.NewMockCommunicator()))
+	}
+	g := NewGame(players[0])
+	for _, p := range players[1:] {
+		g.AddPlayer(p)
+	}
+	assert.Nil(g.ChooseRoleset("Vanilla Fiver"))
+	assert.Nil(g.Start())
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				s := g.Snapshot()
+				for _, p := range s.Players {
+					_ = p.String()
+				}
+				g.IsDay()
+				g.Parity()
+				g.State()
+			}
+		}()
+	}
+
+	var voters sync.WaitGroup
+	for _, p := range players {
+		voters.Add(1)
+		go func(p *player.Player) {
+			defer voters.Done()
+			for i, target := range players {
+				if i%2 == 0 {
+					g.Vote(&player.FingerPoint{From: p, To: target})
+				} else {
+					g.gameChannel <- &gamechannel.Activity{Type: gamechannel.Vote, From: p.ID, Value: target.ID}
+				}
+				g.gameChannel <- &gamechannel.Activity{Type: gamechannel.SetName, From: p.ID, Value: "name"}
+			}
+		}(p)
+	}
+	voters.Wait()
+
+	// wait for the channel to drain before the readers stop
+	assert.Eventually(func() bool { return len(g.gameChannel) == 0 }, time.Second, time.Millisecond)
+	close(done)
+	readers.Wait()
+
+	s := g.Snapshot()
+	assert.Len(s.Players, 5)
+	assert.NotEqual(Setup, s.State)
+}
@@ -3,9 +3,16 @@ package game
 import (
 	"fmt"
 
+	"github.com/awoo-detat/werewolf/player"
 	"github.com/awoo-detat/werewolf/role/roleset"
+
+	"github.com/google/uuid"
 )
 
+// Every error in this file has a stable Code, which is what clients should
+// match on, and Fields describing the specifics. Both are sent to clients in
+// server.ErrorMessage.
+
 type StateError struct {
 	NeedState GameState
 	InState   GameState
@@ -15,6 +22,17 @@ func (e *StateError) Error() string {
 	return fmt.Sprintf("game: in state %v instead of required %v", e.InState, e.NeedState)
 }
 
+func (e *StateError) Code() string {
+	return "invalidState"
+}
+
+func (e *StateError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"needState": e.NeedState.String(),
+		"inState":   e.InState.String(),
+	}
+}
+
 type PlayerCountError struct {
 	Roleset     *roleset.Roleset
 	PlayerCount int
@@ -24,6 +42,18 @@ func (e *PlayerCountError) Error() string {
 	return fmt.Sprintf("game: roleset %s requires %d participants, have %d", e.Roleset.Name, len(e.Roleset.Roles), e.PlayerCount)
 }
 
+func (e *PlayerCountError) Code() string {
+	return "playerCount"
+}
+
+func (e *PlayerCountError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"roleset": e.Roleset.Name,
+		"need":    len(e.Roleset.Roles),
+		"have":    e.PlayerCount,
+	}
+}
+
 type PhaseError struct {
 	GamePhase int
 }
@@ -31,3 +61,306 @@ type PhaseError struct {
 func (e *PhaseError) Error() string {
 	return fmt.Sprintf("game: in invalid phase %v", e.GamePhase)
 }
+
+func (e *PhaseError) Code() string {
+	return "invalidPhase"
+}
+
+func (e *PhaseError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"phase": e.GamePhase,
+	}
+}
+
+type NoRolesetError struct{}
+
+func (e *NoRolesetError) Error() string {
+	return "game: no roleset selected"
+}
+
+func (e *NoRolesetError) Code() string {
+	return "noRoleset"
+}
+
+type RolesetNotFoundError struct {
+	Slug string
+}
+
+func (e *RolesetNotFoundError) Error() string {
+	return fmt.Sprintf("game: roleset %s not found", e.Slug)
+}
+
+func (e *RolesetNotFoundError) Code() string {
+	return "rolesetNotFound"
+}
+
+func (e *RolesetNotFoundError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"roleset": e.Slug,
+	}
+}
+
+// An UnknownPlayerError is returned when an action references a player ID
+// that isn't in the game.
+type UnknownPlayerError struct {
+	ID uuid.UUID
+}
+
+func (e *UnknownPlayerError) Error() string {
+	return fmt.Sprintf("game: player %s is not in this game", e.ID)
+}
+
+func (e *UnknownPlayerError) Code() string {
+	return "unknownPlayer"
+}
+
+func (e *UnknownPlayerError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"player": e.ID,
+	}
+}
+
+type FingerPointError struct {
+	FingerPoint *player.FingerPoint
+}
+
+func (e *FingerPointError) Error() string {
+	return fmt.Sprintf("game: error with FingerPoint: %+v", e.FingerPoint)
+}
+
+func (e *FingerPointError) Code() string {
+	return "invalidFingerPoint"
+}
+
+// A DeadPlayerError is returned when a dead player tries to act, or is the
+// target of an action. Action describes what they could not do.
+type DeadPlayerError struct {
+	Player *player.Player
+	Action string
+}
+
+func (e *DeadPlayerError) Error() string {
+	return fmt.Sprintf("game: %s is dead and cannot %s", e.Player, e.Action)
+}
+
+func (e *DeadPlayerError) Code() string {
+	return "playerDead"
+}
+
+func (e *DeadPlayerError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"player": e.Player.ID,
+		"action": e.Action,
+	}
+}
+
+// A NotLeaderError is returned when a player who isn't the leader tries to
+// do something only the leader may.
+type NotLeaderError struct {
+	Player *player.Player
+}
+
+func (e *NotLeaderError) Error() string {
+	return fmt.Sprintf("game: %s is not the leader", e.Player)
+}
+
+func (e *NotLeaderError) Code() string {
+	return "notLeader"
+}
+
+func (e *NotLeaderError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"player": e.Player.ID,
+	}
+}
+
+// A BannedError is returned when a player tries to rejoin with an account
+// that was kicked.
+type BannedError struct {
+	Player *player.Player
+}
+
+func (e *BannedError) Error() string {
+	return fmt.Sprintf("game: %s was kicked and cannot rejoin", e.Player)
+}
+
+func (e *BannedError) Code() string {
+	return "banned"
+}
+
+type UnsupportedVotingMethodError struct {
+	VotingMethod VotingMethod
+}
+
+func (e *UnsupportedVotingMethodError) Error() string {
+	return fmt.Sprintf("game: voting method %v is not supported", e.VotingMethod)
+}
+
+func (e *UnsupportedVotingMethodError) Code() string {
+	return "unsupportedVotingMethod"
+}
+
+func (e *UnsupportedVotingMethodError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"votingMethod": int(e.VotingMethod),
+	}
+}
+
+type LynchRuleError struct {
+	Rule LynchRule
+}
+
+func (e *LynchRuleError) Error() string {
+	return fmt.Sprintf("game: unknown lynch rule %q", e.Rule)
+}
+
+func (e *LynchRuleError) Code() string {
+	return "unknownLynchRule"
+}
+
+func (e *LynchRuleError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"rule": string(e.Rule),
+	}
+}
+
+// A WaitingForShotError is returned when someone tries to vote or act while
+// the game waits for a dead hunter to shoot.
+type WaitingForShotError struct {
+	Hunter *player.Player
+}
+
+func (e *WaitingForShotError) Error() string {
+	return fmt.Sprintf("game: waiting for %s to take their revenge shot", e.Hunter)
+}
+
+func (e *WaitingForShotError) Code() string {
+	return "waitingForShot"
+}
+
+func (e *WaitingForShotError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"hunter": e.Hunter.ID,
+	}
+}
+
+// A NotShooterError is returned when a player tries to shoot without a
+// revenge shot to take.
+type NotShooterError struct {
+	Player *player.Player
+}
+
+func (e *NotShooterError) Error() string {
+	return fmt.Sprintf("game: %s has no shot to take", e.Player)
+}
+
+func (e *NotShooterError) Code() string {
+	return "notShooter"
+}
+
+func (e *NotShooterError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"player": e.Player.ID,
+	}
+}
+
+type RevealModeError struct {
+	Mode RevealMode
+}
+
+func (e *RevealModeError) Error() string {
+	return fmt.Sprintf("game: unknown reveal mode %q", e.Mode)
+}
+
+func (e *RevealModeError) Code() string {
+	return "unknownRevealMode"
+}
+
+func (e *RevealModeError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"mode": string(e.Mode),
+	}
+}
+
+// A NotCupidError is returned when someone other than a living Cupid tries
+// to choose the lovers.
+type NotCupidError struct {
+	Player *player.Player
+}
+
+func (e *NotCupidError) Error() string {
+	return fmt.Sprintf("game: %s can't choose lovers", e.Player)
+}
+
+func (e *NotCupidError) Code() string {
+	return "notCupid"
+}
+
+func (e *NotCupidError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"player": e.Player.ID,
+	}
+}
+
+// A PotionError is returned when a player chooses a potion they don't have,
+// either because they aren't a Witch, they already used it, or there is no
+// such potion.
+type PotionError struct {
+	Player *player.Player
+	Potion Potion
+}
+
+func (e *PotionError) Error() string {
+	return fmt.Sprintf("game: %s has no %q potion", e.Player, e.Potion)
+}
+
+func (e *PotionError) Code() string {
+	return "noPotion"
+}
+
+func (e *PotionError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"player": e.Player.ID,
+		"potion": string(e.Potion),
+	}
+}
+
+// A NoNightActionError is returned when a player whose role has no night
+// action tries to set one.
+type NoNightActionError struct {
+	Player *player.Player
+}
+
+func (e *NoNightActionError) Error() string {
+	return fmt.Sprintf("game: %s has no night action", e.Player)
+}
+
+func (e *NoNightActionError) Code() string {
+	return "noNightAction"
+}
+
+func (e *NoNightActionError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"player": e.Player.ID,
+	}
+}
+
+// An AccountNameError is returned when a player who signed in tries to change
+// their name, which is their account's.
+type AccountNameError struct {
+	Player *player.Player
+}
+
+func (e *AccountNameError) Error() string {
+	return fmt.Sprintf("game: %s goes by their account's name", e.Player)
+}
+
+func (e *AccountNameError) Code() string {
+	return "accountName"
+}
+
+func (e *AccountNameError) Fields() map[string]interface{} {
+	return map[string]interface{}{
+		"player": e.Player.ID,
+	}
+}
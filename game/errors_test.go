@@ -0,0 +1,74 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel"
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// errorMessages returns the payloads of every Error message sent over c.
+func errorMessages(c *player.MockCommunicator) []*server.ErrorMessage {
+	messages := []*server.ErrorMessage{}
+	for _, raw := range c.Written() {
+		var m struct {
+			Type    server.MessageType   `json:"messageType"`
+			Payload *server.ErrorMessage `json:"payload"`
+		}
+		if err := json.Unmarshal(raw, &m); err == nil && m.Type == server.Error {
+			messages = append(messages, m.Payload)
+		}
+	}
+	return messages
+}
+
+func TestErrorsAreCoded(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGame(player.NewPlayer(player.NewMockCommunicator()))
+
+	err := g.Start()
+	var noRoleset *NoRolesetError
+	assert.True(errors.As(err, &noRoleset))
+
+	assert.Nil(g.ChooseRoleset("Vanilla Fiver"))
+	err = g.Start()
+	m := server.NewErrorMessage("r1", err)
+	assert.Equal("r1", m.RequestID)
+	assert.Equal("playerCount", m.Code)
+	assert.Equal(map[string]interface{}{"roleset": "Vanilla Fiver", "need": 5, "have": 1}, m.Fields)
+
+	err = g.Vote(&player.FingerPoint{})
+	m = server.NewErrorMessage("", err)
+	assert.Equal("invalidState", m.Code)
+	assert.Equal(map[string]interface{}{"needState": "running", "inState": "setup"}, m.Fields)
+
+	m = server.NewErrorMessage("", errors.New("mystery"))
+	assert.Equal(server.GenericErrorCode, m.Code)
+	assert.Equal("mystery", m.Message)
+	assert.Nil(m.Fields)
+}
+
+func TestRejectedActivitiesAreReported(t *testing.T) {
+	c := player.NewMockCommunicator()
+	p := player.NewPlayer(c)
+	g := NewGame(p)
+	target := uuid.New()
+
+	g.gameChannel <- &gamechannel.Activity{Type: gamechannel.Vote, From: p.ID, Value: p.ID, RequestID: "vote-1"}
+	g.gameChannel <- &gamechannel.Activity{Type: gamechannel.NightAction, From: p.ID, Value: target, RequestID: "night-1"}
+
+	assert.Eventually(t, func() bool { return len(errorMessages(c)) == 2 }, time.Second, time.Millisecond)
+	errs := errorMessages(c)
+	assert.Equal(t, "vote-1", errs[0].RequestID)
+	assert.Equal(t, "invalidState", errs[0].Code)
+	assert.Equal(t, "night-1", errs[1].RequestID)
+	assert.Equal(t, "unknownPlayer", errs[1].Code)
+	assert.Equal(t, target.String(), errs[1].Fields["player"])
+}
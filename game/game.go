@@ -1,39 +1,98 @@
 package game
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
-	"math"
 	"math/rand"
 	"slices"
+	"sync"
+	"time"
 
 	"github.com/awoo-detat/werewolf/gamechannel"
+	"github.com/awoo-detat/werewolf/gamechannel/client"
 	"github.com/awoo-detat/werewolf/gamechannel/server"
 	"github.com/awoo-detat/werewolf/player"
 	"github.com/awoo-detat/werewolf/role"
 	"github.com/awoo-detat/werewolf/role/roleset"
 	"github.com/awoo-detat/werewolf/tally"
+	"github.com/awoo-detat/werewolf/win"
 
 	"github.com/google/uuid"
 )
 
+// A Game is safe for concurrent use: every exported method takes the game's
+// lock, as does the handling of each activity from the game channel. Its
+// state is unexported and only touched under that lock; other goroutines read
+// it through Snapshot and the other accessors. The exported fields are
+// settings, to be set before the game is shared; the ones the leader chooses
+// change after that only through SetLynchRule and SetRevealMode.
+//
+// A game runs until its context is cancelled: by Close, by the parent context
+// passed to NewGameContext, Linger after it finishes, or AbandonAfter once no
+// player is connected. When it stops, its players' sockets are closed.
+//
+// OnGameOver, if set, is given the Result of each game played, in its own
+// goroutine. OnPasswordChange, if set, is called whenever the game's password
+// changes, ie when a player is kicked, with the old password and the new one.
+// It is called with the game's lock held, so it must not call back into the
+// game.
 type Game struct {
-	ID           uuid.UUID
-	Leader       *player.Player
-	VotingMethod VotingMethod
-	AlivePlayers map[uuid.UUID]*player.Player
-	Players      map[uuid.UUID]*player.Player
-	playerSlice  []*player.Player
-	Roleset      *roleset.Roleset
-	state        GameState
-	Phase        int
-	Tally        *tally.Tally
-	nightActions map[*player.Player]*player.FingerPoint
-	Winner       role.PlayerType
-	gameChannel  gamechannel.GameChannel
-	Password     string
+	ID               uuid.UUID
+	leader           *player.Player
+	VotingMethod     VotingMethod
+	LynchRule        LynchRule
+	DayLength        time.Duration
+	dayTimer         *time.Timer
+	deadline         time.Time
+	ShotTime         time.Duration
+	RevealMode       RevealMode
+	shots            []*pendingShot
+	endingPhase      bool
+	alivePlayers     map[uuid.UUID]*player.Player
+	players          map[uuid.UUID]*player.Player
+	joined           []*player.Player
+	departed         []*player.Player
+	vacant           map[uuid.UUID]bool
+	substitutes      map[uuid.UUID]bool
+	QuitPolicy       QuitPolicy
+	bannedAccts      map[uuid.UUID]bool
+	playerSlice      []*player.Player
+	roleset          *roleset.Roleset
+	state            GameState
+	phase            int
+	tally            *tally.Tally
+	voteHistory      []*tally.Day
+	nightActions     map[*player.Player]*player.FingerPoint
+	nightKill        *player.FingerPoint
+	lovers           []*player.Player
+	potionChoices    map[*player.Player]*potionChoice
+	usedPotions      map[uuid.UUID]map[Potion]bool
+	timeline         []*server.TimelineEvent
+	winner           role.PlayerType
+	soloWinners      map[uuid.UUID]bool
+	gameChannel      gamechannel.GameChannel
+	password         string
+	Linger           time.Duration
+	AbandonAfter     time.Duration
+	OnGameOver       func(*Result)
+	OnPasswordChange func(from, to string)
+	connected        map[uuid.UUID]bool
+	stopTimer        *time.Timer
+	ctx              context.Context
+	cancel           context.CancelFunc
+	mu               sync.Mutex
 }
 
+const (
+	// DefaultLinger is how long a finished game stays up, so that players can
+	// see the result and reconnect to it.
+	DefaultLinger = 5 * time.Minute
+	// DefaultAbandonAfter is how long a game waits for someone to reconnect
+	// once every player has disconnected.
+	DefaultAbandonAfter = 10 * time.Minute
+)
+
 type GameState int
 
 const (
@@ -42,6 +101,31 @@ const (
 	Finished
 )
 
+func (s GameState) String() string {
+	switch s {
+	case Setup:
+		return "setup"
+	case Running:
+		return "running"
+	case Finished:
+		return "finished"
+	}
+	return ""
+}
+
+// A QuitPolicy says what happens to a player who quits a running game.
+type QuitPolicy int
+
+const (
+	// ModKill kills the player, revealing them and checking whether the game
+	// is over, as if they had been lynched.
+	ModKill QuitPolicy = iota
+	// HoldForSubstitute keeps the player's seat, and their role, for the next
+	// person to join the game. The game waits for them if it needs to, ie for
+	// a night action.
+	HoldForSubstitute
+)
+
 type VotingMethod int
 
 const (
@@ -54,89 +138,324 @@ const (
 )
 
 func NewGame(p *player.Player) *Game {
-	pw, err := passwordGenerator.Generate()
-	if err != nil {
-		slog.Error("error generating password", "error", err)
-	}
+	return NewGameContext(context.Background(), p)
+}
+
+// NewGameContext creates a game that stops when ctx is cancelled.
+func NewGameContext(ctx context.Context, p *player.Player) *Game {
 	g := &Game{
-		ID:           uuid.New(),
-		Players:      make(map[uuid.UUID]*player.Player),
-		VotingMethod: InstaKill,
-		AlivePlayers: make(map[uuid.UUID]*player.Player),
-		nightActions: make(map[*player.Player]*player.FingerPoint),
-		playerSlice:  []*player.Player{},
-		gameChannel:  make(gamechannel.GameChannel),
-		Password:     pw.String(),
+		ID:            uuid.New(),
+		players:       make(map[uuid.UUID]*player.Player),
+		VotingMethod:  InstaKill,
+		LynchRule:     Half,
+		DayLength:     DefaultDayLength,
+		ShotTime:      DefaultShotTime,
+		RevealMode:    RevealRole,
+		alivePlayers:  make(map[uuid.UUID]*player.Player),
+		nightActions:  make(map[*player.Player]*player.FingerPoint),
+		potionChoices: make(map[*player.Player]*potionChoice),
+		usedPotions:   make(map[uuid.UUID]map[Potion]bool),
+		playerSlice:   []*player.Player{},
+		gameChannel:   make(gamechannel.GameChannel),
+		password:      newPassword(),
+		Linger:        DefaultLinger,
+		AbandonAfter:  DefaultAbandonAfter,
+		connected:     make(map[uuid.UUID]bool),
+		bannedAccts:   make(map[uuid.UUID]bool),
+		vacant:        make(map[uuid.UUID]bool),
+		substitutes:   make(map[uuid.UUID]bool),
+		soloWinners:   make(map[uuid.UUID]bool),
 	}
-	g.AddPlayer(p)
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	g.addPlayer(p)
 
 	go g.ListenToGameChannel()
 	return g
 }
 
-func (g *Game) SetLeader(p *player.Player) {
+func (g *Game) setLeader(p *player.Player) {
 	slog.Info("setting leader", "player", p)
-	g.Leader = p
-	p.Message(server.RolesetList, roleset.List())
+	g.leader = p
+	g.sendLeaderMessages()
 }
 
-func (g *Game) SendLeaderMessages() {
-	if g.Leader == nil {
+func (g *Game) sendLeaderMessages() {
+	if g.leader == nil {
 		slog.Error("leader is nil")
 		return
 	}
-	g.Leader.Message(server.RolesetList, roleset.List())
-	g.Leader.Message(server.Password, g.Password)
+	g.leader.Message(server.RolesetList, roleset.List())
+	g.leader.Message(server.Password, g.password)
 }
 
-func (g *Game) State() GameState {
-	return g.state
+func (g *Game) addPlayer(p *player.Player) error {
+	if g.state != Setup {
+		return &StateError{NeedState: Setup, InState: g.state}
+	}
+	if g.isBanned(p) {
+		return &BannedError{Player: p}
+	}
+	if len(g.players) == 0 {
+		g.setLeader(p)
+	}
+	p.SetGameChannel(g.gameChannel, g.ctx.Done())
+	g.players[p.ID] = p
+	g.joined = append(g.joined, p)
+	g.connected[p.ID] = true
+	slog.Info("player added", "player", p)
+	p.Message(server.LeaderSet, g.leader)
+	p.Message(server.LynchRuleSet, g.lynchRuleMessage())
+	p.Message(server.RevealModeSet, g.RevealMode)
+	g.broadcast(server.PlayerJoin, p)
+	g.broadcastPlayerList()
+	return nil
 }
 
-func (g *Game) AddPlayer(p *player.Player) {
-	if g.state != Setup {
-		p.Message(server.Error, "game is in progress")
+// removePlayer takes p out of the game, handing on leadership if they had it.
+func (g *Game) removePlayer(p *player.Player) {
+	delete(g.players, p.ID)
+	delete(g.connected, p.ID)
+	g.joined = slices.DeleteFunc(g.joined, func(j *player.Player) bool { return j == p })
+	g.broadcast(server.PlayerLeave, p)
+	g.broadcastPlayerList()
+	if g.leader == p {
+		g.succeedLeader()
+	}
+}
+
+// succeedLeader makes whoever joined first, preferring players who are still
+// connected, the new leader.
+func (g *Game) succeedLeader() {
+	var next *player.Player
+	for _, p := range g.joined {
+		if g.connected[p.ID] {
+			next = p
+			break
+		}
+	}
+	if next == nil && len(g.joined) > 0 {
+		next = g.joined[0]
+	}
+	if next == nil {
+		slog.Info("nobody left to lead", "game", g.ID)
+		g.leader = nil
 		return
 	}
-	if len(g.Players) == 0 {
-		g.SetLeader(p)
+	g.setLeader(next)
+	g.broadcast(server.LeaderSet, g.leader)
+}
+
+// quitMidGame handles a player leaving a running game according to its
+// QuitPolicy. Either way their vote and night action are withdrawn.
+func (g *Game) quitMidGame(p *player.Player) {
+	slog.Info("player quit mid-game", "player", p, "policy", g.QuitPolicy)
+	delete(g.connected, p.ID)
+	g.joined = slices.DeleteFunc(g.joined, func(j *player.Player) bool { return j == p })
+	g.broadcast(server.PlayerLeave, p)
+	if g.leader == p {
+		g.succeedLeader()
+	}
+
+	g.recordUnvote(p)
+	switch g.QuitPolicy {
+	case HoldForSubstitute:
+		if g.tally != nil {
+			g.tally.Unvote(p)
+		}
+		g.withdrawNightAction(p)
+		g.vacant[p.ID] = true
+	default:
+		if p.Role.Alive {
+			if g.tally != nil {
+				g.tally.Remove(p)
+			}
+			g.withdrawNightAction(p)
+			g.withdrawNightActionsOn(p)
+			g.killPlayer(p, server.ModKilled)
+		}
+		// nobody is left to take their shot
+		g.dropShot(p)
+		delete(g.players, p.ID)
+		g.departed = append(g.departed, p)
+		p.Close()
+	}
+
+	if g.state == Running {
+		g.resume()
+	}
+}
+
+// withdrawNightAction forgets p's night action. If it was the night kill, the
+// kill falls to another wolf's choice, if there is one.
+func (g *Game) withdrawNightAction(p *player.Player) {
+	delete(g.nightActions, p)
+	delete(g.potionChoices, p)
+	if g.nightKill == nil || g.nightKill.From != p {
+		return
+	}
+	g.nightKill = nil
+	for _, fp := range g.nightActions {
+		if fp.From.Role.CanNightKill() {
+			g.nightKill = fp
+			break
+		}
+	}
+}
+
+// withdrawNightActionsOn forgets the night actions targeting p, telling
+// whoever chose them to choose again.
+func (g *Game) withdrawNightActionsOn(p *player.Player) {
+	for from, fp := range g.nightActions {
+		if fp.To != p {
+			continue
+		}
+		g.withdrawNightAction(from)
+		from.SendError("", &DeadPlayerError{Player: p, Action: "be targeted by a night action"})
+	}
+	for witch, c := range g.potionChoices {
+		if c.target != p {
+			continue
+		}
+		delete(g.potionChoices, witch)
+		witch.SendError("", &DeadPlayerError{Player: p, Action: "be targeted by a potion"})
+	}
+}
+
+// resume moves the game on if a player leaving was what it was waiting for.
+func (g *Game) resume() {
+	if g.isDay() {
+		if g.tally == nil {
+			return
+		}
+		g.broadcastTally()
+		g.checkForInstaKillDayEnd()
+		return
+	}
+	g.checkNightActions()
+}
+
+// substitute seats a newcomer in a vacant seat: they become that player,
+// keeping their ID, name, role and views, once they connect. The seat's
+// result goes to the newcomer's account, if they have one.
+func (g *Game) substitute(addr string, accountID uuid.UUID) (*player.Player, bool) {
+	for _, p := range g.playerSlice {
+		if !g.vacant[p.ID] {
+			continue
+		}
+		slog.Info("seat claimed by a substitute", "player", p)
+		delete(g.vacant, p.ID)
+		g.substitutes[p.ID] = true
+		p.Addr = addr
+		p.AccountID = accountID
+		return p, true
+	}
+	return nil, false
+}
+
+func (g *Game) transferLeader(fp *player.FingerPoint) error {
+	if fp.From != g.leader {
+		return &NotLeaderError{Player: fp.From}
+	}
+	g.setLeader(fp.To)
+	g.broadcast(server.LeaderSet, g.leader)
+	return nil
+}
+
+// kick removes a player from a game that hasn't started. The password they
+// joined with is changed, and the new one sent to the leader, so they need to
+// be invited again to come back; if they signed in, their account is banned
+// so that they can't be. Addresses aren't banned, since players behind the
+// same NAT or on the same host share one.
+func (g *Game) kick(fp *player.FingerPoint) error {
+	if fp.From != g.leader {
+		return &NotLeaderError{Player: fp.From}
+	}
+	if g.state != Setup {
+		return &StateError{NeedState: Setup, InState: g.state}
+	}
+	if fp.To == fp.From {
+		return &FingerPointError{FingerPoint: fp}
+	}
+	p := fp.To
+	slog.Info("kicking player", "player", p, "account", p.AccountID)
+	if p.AccountID != uuid.Nil {
+		g.bannedAccts[p.AccountID] = true
+	}
+	p.Message(server.Kicked, nil)
+	g.removePlayer(p)
+	p.Close()
+	g.changePassword()
+	return nil
+}
+
+// changePassword gives the game a new password, and tells the leader what it
+// is.
+func (g *Game) changePassword() {
+	old := g.password
+	for g.password == old {
+		// there aren't many passwords, so the same one can come up again
+		if g.password = newPassword(); g.password == "" {
+			break
+		}
+	}
+	slog.Info("password changed", "game", g.ID)
+	if g.leader != nil {
+		g.leader.Message(server.Password, g.password)
+	}
+	if g.OnPasswordChange != nil {
+		g.OnPasswordChange(old, g.password)
 	}
-	p.SetGameChannel(g.gameChannel)
-	g.Players[p.ID] = p
-	slog.Info("player added", "player", p)
-	p.Message(server.LeaderSet, g.Leader)
-	g.Broadcast(server.PlayerJoin, p)
-	g.BroadcastPlayerList()
 }
 
-func (g *Game) ChooseRoleset(slug string) error {
+// setName renames p. A player who signed in goes by their account's name, so
+// that it's the same in every game they play.
+func (g *Game) setName(p *player.Player, name string) error {
+	if p.AccountID != uuid.Nil {
+		return &AccountNameError{Player: p}
+	}
+	p.SetName(name)
+	g.broadcastPlayerList()
+	return nil
+}
+
+func (g *Game) isBanned(p *player.Player) bool {
+	return p.AccountID != uuid.Nil && g.bannedAccts[p.AccountID]
+}
+
+func (g *Game) chooseRoleset(slug string) error {
 	if g.state > Setup {
 		return &StateError{NeedState: Setup, InState: g.state}
 	}
 	rs, ok := roleset.List()[slug]
 	if !ok {
-		return fmt.Errorf("roleset %s not found", slug)
+		return &RolesetNotFoundError{Slug: slug}
 	}
 
-	g.Roleset = rs
+	// every game needs its own roles, since they are killed off
+	g.roleset = rs.Clone()
 	slog.Info("roleset chosen", "roleset", rs)
-	g.Broadcast(server.RolesetSelected, rs)
+	g.broadcast(server.RolesetSelected, g.roleset)
 	return nil
 }
 
 func (g *Game) assignRoles() error {
-	if g.Roleset == nil {
-		return fmt.Errorf("game: no roleset defined") // TODO
+	if g.roleset == nil {
+		return &NoRolesetError{}
 	}
-	if len(g.Players) != len(g.Roleset.Roles) {
-		return &PlayerCountError{Roleset: g.Roleset, PlayerCount: len(g.Players)}
+	if len(g.players) != len(g.roleset.Roles) {
+		return &PlayerCountError{Roleset: g.roleset, PlayerCount: len(g.players)}
 	}
 
-	for playerKey, roleKey := range rand.Perm(len(g.Players)) {
+	// nobody is told they are a Tinker until the game is over
+	g.roleset.ApplyTinkers()
+	for playerKey, roleKey := range rand.Perm(len(g.players)) {
 		p := g.playerSlice[playerKey]
-		r := g.Roleset.Roles[roleKey]
+		r := g.roleset.Roles[roleKey]
 		p.SetRole(r)
-		slog.Info("assigning role", "player", p, "role", r)
+		slog.Info("assigning role", "player", p, "role", r, "tinker", r.IsTinker())
+		if r.IsTinker() {
+			g.record(&server.TimelineEvent{Type: server.TinkerAssigned, To: p.ID})
+		}
 	}
 
 	return nil
@@ -145,7 +464,10 @@ func (g *Game) assignRoles() error {
 // this selects random clears for those that get them, and informs
 // the roles that know maxes of those players
 func (g *Game) processN0() {
-	for _, p := range g.Players {
+	if g.roleset.Lovers && !g.awaitingCupid() {
+		g.randomLovers()
+	}
+	for _, p := range g.players {
 		if p.Role.CanViewForMax() && p.Role.HasRandomN0Clear() {
 			view := g.randomClear(p, func(r *role.Role) bool { return r.ViewForMaxEvil() })
 			g.nightActions[p] = &player.FingerPoint{From: p, To: view}
@@ -162,18 +484,18 @@ func (g *Game) processN0() {
 		}
 
 		if p.Role.KnowsMaxes() {
-			for _, m := range g.AliveMaxEvils() {
+			for _, m := range g.aliveMaxEvils() {
 				if m != p {
-					p.AddView(player.NewAttributeView(m, role.MaxEvilAttribute, true, g.Phase))
+					p.AddView(player.NewAttributeView(m, role.MaxEvilAttribute, true, g.phase))
 				}
 			}
 		}
 	}
-	g.processNightActions()
+	g.checkNightActions()
 }
 
 func (g *Game) randomClear(p *player.Player, test func(*role.Role) bool) *player.Player {
-	for _, i := range rand.Perm(len(g.Players)) {
+	for _, i := range rand.Perm(len(g.players)) {
 		view := g.playerSlice[i]
 		if view == p {
 			continue
@@ -186,22 +508,22 @@ func (g *Game) randomClear(p *player.Player, test func(*role.Role) bool) *player
 	return nil // should be impossible
 }
 
-func (g *Game) Start() error {
+func (g *Game) start() error {
 	if g.state > Setup {
 		return &StateError{NeedState: Setup, InState: g.state}
 	}
-	if g.Roleset == nil {
-		return fmt.Errorf("game: cannot start, no roleset selected")
+	if g.roleset == nil {
+		return &NoRolesetError{}
 	}
-	if len(g.Players) != len(g.Roleset.Roles) {
-		return fmt.Errorf("game: cannot start; have %v players, need %v", len(g.Players), len(g.Roleset.Roles))
+	if len(g.players) != len(g.roleset.Roles) {
+		return &PlayerCountError{Roleset: g.roleset, PlayerCount: len(g.players)}
 	}
 	slog.Info("starting game")
 
 	// fill in the slice of players now that we have them all
-	for _, p := range g.Players {
+	for _, p := range g.players {
 		g.playerSlice = append(g.playerSlice, p)
-		g.AlivePlayers[p.ID] = p
+		g.alivePlayers[p.ID] = p
 	}
 
 	if err := g.assignRoles(); err != nil {
@@ -215,133 +537,302 @@ func (g *Game) Start() error {
 }
 
 func (g *Game) nextPhase() {
-	g.Phase++
-	slog.Info("new phase", "phase", g.Phase)
-	g.Broadcast(server.AlivePlayerList, g.alivePlayerList())
+	g.archiveDay()
+	g.phase++
+	g.endingPhase = false
+	slog.Info("new phase", "phase", g.phase)
+	g.broadcast(server.AlivePlayerList, g.alivePlayerList())
 
 	// new day new me, reset everything
-	if g.IsDay() {
-		g.Broadcast(server.PhaseChanged, &server.Phase{Phase: server.Day, Count: g.Phase})
-		g.Tally = tally.New(g.alivePlayerList())
-		g.Broadcast(server.TallyChanged, g.Tally)
+	if g.isDay() {
+		g.broadcast(server.PhaseChanged, &server.Phase{Phase: server.Day, Count: g.phase})
+		g.tally = tally.New(g.alivePlayerList())
+		g.startDayTimer()
+		g.broadcastTally()
 		g.nightActions = make(map[*player.Player]*player.FingerPoint)
+		g.potionChoices = make(map[*player.Player]*potionChoice)
+		g.nightKill = nil
 	} else {
-		g.Broadcast(server.PhaseChanged, &server.Phase{Phase: server.Night, Count: g.Phase})
+		g.stopDayTimer()
+		g.broadcast(server.PhaseChanged, &server.Phase{Phase: server.Night, Count: g.phase})
+		// if nobody left has a night action, there is nothing to wait for
+		g.checkNightActions()
 	}
 }
 
-func (g *Game) Vote(fp *player.FingerPoint) error {
+// moveOn goes on to the next phase, unless the game is over or waiting for a
+// revenge shot, in which case it moves on once the shots have been taken.
+// Whatever is ending the phase sets endingPhase before anyone dies, so that a
+// hunter killed on the way knows the phase is over.
+func (g *Game) moveOn() {
+	if g.state != Running || len(g.shots) > 0 {
+		return
+	}
+	// a phase change can end the game as well as a death, ie a day
+	// that ends without a lynch under some rules
+	g.checkForWin()
+	if g.state == Running {
+		g.nextPhase()
+	}
+}
+
+func (g *Game) vote(fp *player.FingerPoint) error {
 	if g.state != Running {
 		return &StateError{NeedState: Running, InState: g.state}
 	}
-	if g.IsNight() {
-		return &PhaseError{GamePhase: g.Phase}
+	if g.isNight() {
+		return &PhaseError{GamePhase: g.phase}
+	}
+	if hunter := g.shooter(); hunter != nil {
+		return &WaitingForShotError{Hunter: hunter}
 	}
 
 	if fp == nil || fp.From == nil || fp.To == nil {
-		return fmt.Errorf("error with FingerPoint: %+v", fp)
+		return &FingerPointError{FingerPoint: fp}
 	}
 
 	if !fp.From.Role.Alive {
-		return fmt.Errorf("%s is dead and cannot vote", fp.From)
+		return &DeadPlayerError{Player: fp.From, Action: "vote"}
 	}
 	if !fp.To.Role.Alive {
-		return fmt.Errorf("%s is dead and cannot be voted for", fp.To)
+		return &DeadPlayerError{Player: fp.To, Action: "be voted for"}
 	}
 
-	g.Tally.Vote(fp)
-	g.Broadcast(server.TallyChanged, g.Tally)
+	if !g.tally.Vote(fp) {
+		// they already vote for fp.To
+		return nil
+	}
+	g.record(&server.TimelineEvent{
+		Type: server.VoteCast,
+		Time: g.tally.Inverted[fp.From].Timestamp,
+		From: fp.From.ID,
+		To:   fp.To.ID,
+	})
+	g.broadcastTally()
 
 	switch g.VotingMethod {
 	case InstaKill:
 		g.checkForInstaKillDayEnd()
-	case InstaKillWithDelay:
-		return fmt.Errorf("InstaKillWithDelay is not supported")
-	case Timed:
-		return fmt.Errorf("Timed is not supported")
+	default:
+		return &UnsupportedVotingMethodError{VotingMethod: g.VotingMethod}
 	}
 
 	return nil
 }
 
 func (g *Game) checkForInstaKillDayEnd() {
-	leader := g.Tally.List[0]
-	// TODO? if there's an even number this is first-to-half...
-	need := math.Ceil(float64(len(g.AlivePlayers)) / 2)
-	have := float64(len(leader.Votes))
+	if len(g.shots) > 0 {
+		return
+	}
+	need := g.LynchRule.VotesNeeded(len(g.alivePlayers))
+	if need == 0 {
+		// the day only ends at the deadline
+		return
+	}
+	leader := g.tally.List[0]
+	have := len(leader.Votes)
 	if have < need {
 		slog.Info("day not over", "have", have, "need", need)
 		return
 	}
 
-	g.KillPlayer(leader.Player)
-	if g.state == Running {
-		g.nextPhase()
-	}
+	g.endingPhase = true
+	g.killPlayer(leader.Player, server.Lynched)
+	g.moveOn()
 }
 
-func (g *Game) KillPlayer(p *player.Player) {
-	slog.Info("killing player", "player", p)
+func (g *Game) killPlayer(p *player.Player, cause server.DeathCause) {
+	slog.Info("killing player", "player", p, "cause", cause)
 	killed := p.Role.Kill()
 	if !killed {
 		return
 	}
-	delete(g.AlivePlayers, p.ID)
+	g.record(&server.TimelineEvent{Type: server.Death, To: p.ID, Cause: cause})
+	delete(g.alivePlayers, p.ID)
+	if cause == server.Lynched && p.Role.WinCondition == role.WinIfLynched {
+		slog.Info("player wins by being lynched", "player", p)
+		g.soloWinners[p.ID] = true
+	}
 	p.Message(server.PlayerKilled, nil)
-	g.RevealPlayer(p)
+	g.revealPlayer(p)
+	if partner := g.partner(p); partner != nil && partner.Role.Alive {
+		slog.Info("lover dies of a broken heart", "player", partner)
+		g.killPlayer(partner, server.Heartbreak)
+		if g.state != Running {
+			return
+		}
+	}
+	if p.Role.HasRevengeShot() && cause != server.ModKilled {
+		// the shot may change who wins, so the win check waits for it
+		g.holdForShot(p)
+		return
+	}
+	g.checkForWin()
+}
 
-	maxes, nonmaxes := g.AlivePlayersByType()
-	parity := g.Parity()
-	equality := len(maxes) == len(nonmaxes)
-	slog.Info("checking for game end", "parity", parity, "equality", equality)
-	switch {
-	case len(maxes) == 0:
-		g.EndGame(role.Good)
-	case parity < 0:
-		g.EndGame(role.Evil)
-	case parity == 0 && equality:
-		g.EndGame(role.Evil)
-	case equality:
-		// due to a hunter, evil loses
-		// TODO: what will/should happen with ancient WW vs hunter?
-		g.EndGame(role.Good)
+// checkForWin ends the game if the roleset's win condition says it is over.
+func (g *Game) checkForWin() {
+	if len(g.shots) > 0 {
+		// the shot may change who wins
+		return
 	}
+	alive := []*role.Role{}
+	for _, p := range g.alivePlayers {
+		alive = append(alive, p.Role)
+	}
+	condition := g.winCondition()
+	winner, over := condition.Check(alive)
+	slog.Info("checking for game end", "condition", fmt.Sprintf("%T", condition), "over", over, "winner", winner)
+	if !over {
+		return
+	}
+	if winner == role.Neutral {
+		solo := win.NeutralWinners(alive)
+		for _, p := range g.alivePlayers {
+			if slices.Contains(solo, p.Role) {
+				g.soloWinners[p.ID] = true
+			}
+		}
+	}
+	g.endGame(winner)
+}
+
+func (g *Game) winCondition() win.Condition {
+	if g.roleset != nil && g.roleset.Win != nil {
+		return g.roleset.Win
+	}
+	return win.Parity{}
 }
 
-func (g *Game) Parity() int {
+func (g *Game) parity() int {
 	parity := 0
-	for _, p := range g.AlivePlayers {
+	for _, p := range g.alivePlayers {
 		parity += p.Role.Parity
 	}
 	slog.Info("parity calculated", "parity", parity)
 	return parity
 }
 
-func (g *Game) EndGame(winner role.PlayerType) {
+func (g *Game) endGame(winner role.PlayerType) {
 	slog.Info("game over", "winner", winner)
 	g.state = Finished
-	g.Winner = winner
-	g.Broadcast(server.GameOver, g.ToGameOverMessage())
+	g.winner = winner
+	g.stopDayTimer()
+	g.stopShots()
+	g.archiveDay()
+	g.broadcast(server.GameOver, g.toGameOverMessage())
+	g.broadcast(server.Timeline, g.timeline)
+	if g.OnGameOver != nil {
+		go g.OnGameOver(g.result())
+	}
+	g.stopAfter(g.Linger)
+}
+
+// reset puts a finished game back into setup, keeping its players, leader,
+// password and roleset, so that the same group can play again.
+func (g *Game) reset(p *player.Player) error {
+	if p != g.leader {
+		return &NotLeaderError{Player: p}
+	}
+	if g.state != Finished {
+		return &StateError{NeedState: Finished, InState: g.state}
+	}
+	slog.Info("resetting game", "game", g.ID)
+
+	for _, p := range g.players {
+		p.Reset()
+	}
+	g.state = Setup
+	g.phase = 0
+	g.endingPhase = false
+	g.tally = nil
+	g.stopDayTimer()
+	g.stopShots()
+	g.voteHistory = nil
+	g.winner = 0
+	g.soloWinners = make(map[uuid.UUID]bool)
+	g.alivePlayers = make(map[uuid.UUID]*player.Player)
+	g.playerSlice = []*player.Player{}
+	g.nightActions = make(map[*player.Player]*player.FingerPoint)
+	g.nightKill = nil
+	g.potionChoices = make(map[*player.Player]*potionChoice)
+	g.usedPotions = make(map[uuid.UUID]map[Potion]bool)
+	g.lovers = nil
+	g.timeline = nil
+	g.departed = nil
+	g.vacant = make(map[uuid.UUID]bool)
+	g.substitutes = make(map[uuid.UUID]bool)
+	if g.roleset != nil {
+		// the old roles are dead, so deal from a fresh copy
+		if rs, ok := roleset.List()[g.roleset.Name]; ok {
+			g.roleset = rs.Clone()
+		}
+	}
+
+	// a finished game is due to stop; this one isn't finished any more
+	if g.stopTimer != nil {
+		g.stopTimer.Stop()
+		g.stopTimer = nil
+	}
+	g.checkAbandoned()
+
+	g.broadcast(server.GameReset, nil)
+	g.broadcast(server.LeaderSet, g.leader)
+	if g.roleset != nil {
+		g.broadcast(server.RolesetSelected, g.roleset)
+	}
+	g.broadcastPlayerList()
+	g.sendLeaderMessages()
+	return nil
 }
 
-func (g *Game) ToGameOverMessage() *server.GameOverMessage {
+func (g *Game) toGameOverMessage() *server.GameOverMessage {
 	players := []*server.RevealedPlayer{}
-	for _, p := range g.Players {
+	winners := []uuid.UUID{}
+	reveal := func(p *player.Player) {
 		players = append(players, p.Reveal())
+		if g.won(p) {
+			winners = append(winners, p.ID)
+		}
+	}
+	for _, p := range g.players {
+		reveal(p)
+	}
+	for _, p := range g.departed {
+		reveal(p)
 	}
 	return &server.GameOverMessage{
-		Winner: g.Winner,
-		Roles:  players,
+		Winner:  g.winner,
+		Winners: winners,
+		Roles:   players,
+	}
+}
+
+// won reports whether a player won the game: with their team, or on their
+// own terms if they are neutral.
+func (g *Game) won(p *player.Player) bool {
+	if g.state != Finished || p.Role == nil {
+		return false
 	}
+	if g.soloWinners[p.ID] {
+		return true
+	}
+	if p.Role.IsLover() {
+		// lovers from different teams can only win together
+		if partner := g.partner(p); partner != nil && partner.Role.Team != p.Role.Team {
+			return false
+		}
+	}
+	return p.Role.WinCondition == role.WinWithTeam && p.Role.Team == g.winner
 }
 
-func (g *Game) AliveMaxEvils() []*player.Player {
-	maxes, _ := g.AlivePlayersByType()
+func (g *Game) aliveMaxEvils() []*player.Player {
+	maxes, _ := g.alivePlayersByType()
 	return maxes
 }
 
-func (g *Game) AlivePlayersByType() (maxes []*player.Player, nonmaxes []*player.Player) {
-	for _, p := range g.AlivePlayers {
+func (g *Game) alivePlayersByType() (maxes []*player.Player, nonmaxes []*player.Player) {
+	for _, p := range g.alivePlayers {
 		if p.Role.IsMaxEvil() {
 			maxes = append(maxes, p)
 		} else {
@@ -351,31 +842,70 @@ func (g *Game) AlivePlayersByType() (maxes []*player.Player, nonmaxes []*player.
 	return
 }
 
-func (g *Game) IsDay() bool {
-	return g.Phase%2 == 1
+func (g *Game) isDay() bool {
+	return g.phase%2 == 1
 }
 
-func (g *Game) IsNight() bool {
-	return !g.IsDay()
+func (g *Game) isNight() bool {
+	return !g.isDay()
 }
 
-func (g *Game) SetNightAction(fp *player.FingerPoint) error {
+func (g *Game) setNightAction(fp *player.FingerPoint) error {
+	if g.state != Running {
+		return &StateError{NeedState: Running, InState: g.state}
+	}
 	if fp == nil || fp.From == nil || fp.To == nil {
-		return fmt.Errorf("error with FingerPoint: %+v", fp)
+		return &FingerPointError{FingerPoint: fp}
+	}
+	if hunter := g.shooter(); hunter != nil {
+		return &WaitingForShotError{Hunter: hunter}
+	}
+	if g.phase == 0 {
+		return &PhaseError{GamePhase: g.phase}
 	}
 	if !fp.From.Role.Alive {
-		return fmt.Errorf("%s is dead and cannot have a night action", fp.From)
+		return &DeadPlayerError{Player: fp.From, Action: "have a night action"}
+	}
+	if !hasNightAction(fp.From.Role) {
+		return &NoNightActionError{Player: fp.From}
 	}
 	if !fp.To.Role.Alive {
-		return fmt.Errorf("%s is dead and cannot be targeted by a night action", fp.To)
+		return &DeadPlayerError{Player: fp.To, Action: "be targeted by a night action"}
 	}
 
 	slog.Info("setting night action", "fingerpoint", fp)
 
 	// this allows you to change your mind and choose someone else
 	g.nightActions[fp.From] = fp
+	g.record(&server.TimelineEvent{Type: server.NightAction, From: fp.From.ID, To: fp.To.ID})
+	if fp.From.Role.CanNightKill() {
+		// if there are multiple wolves, the most recent choice is the one that counts
+		g.nightKill = fp
+	}
+	g.checkNightActions()
+	return nil
+}
+
+// checkNightActions ends the night once everyone who has a night action has
+// chosen it.
+func (g *Game) checkNightActions() {
+	if len(g.shots) > 0 {
+		return
+	}
+	if g.phase == 0 {
+		// the only choice made on night 0 is Cupid's
+		if !g.awaitingCupid() {
+			g.processNightActions()
+		}
+		return
+	}
 	neededPlayers := g.alivePlayersWithNightActions()
 	neededPlayers = slices.DeleteFunc(neededPlayers, func(p *player.Player) bool {
+		// a Witch's choice is their potion, even if it's NoPotion
+		if !hasNightAction(p.Role) {
+			_, ok := g.potionChoices[p]
+			return ok
+		}
 		_, ok := g.nightActions[p]
 		return ok
 	})
@@ -384,73 +914,113 @@ func (g *Game) SetNightAction(fp *player.FingerPoint) error {
 	} else {
 		slog.Info("still need night actions", "needed", neededPlayers)
 	}
-	return nil
 }
 
 func (g *Game) alivePlayersWithNightActions() []*player.Player {
 	players := []*player.Player{}
-	for _, p := range g.AlivePlayers {
-		switch {
-		case p.Role.CanViewForMax():
-			players = append(players, p)
-		case p.Role.CanNightKill():
-			players = append(players, p)
-		case p.Role.CanViewForSeer():
-			players = append(players, p)
-		case p.Role.CanViewForAux():
+	for _, p := range g.alivePlayers {
+		if hasNightAction(p.Role) || g.hasPotions(p) {
 			players = append(players, p)
 		}
 	}
 	return players
 }
 
+// hasNightAction reports whether r chooses a target each night with
+// setNightAction. A Witch's potions are chosen separately.
+func hasNightAction(r *role.Role) bool {
+	return r.CanViewForMax() || r.CanNightKill() || r.CanSoloKill() ||
+		r.CanViewForSeer() || r.CanViewForAux() || r.CanRoleblock()
+}
+
+// processNightActions resolves the night in order. Roleblocks come first,
+// so that whatever a blocked player chose never happens. Then views are
+// given, so a viewer who dies tonight still learns what they saw. Then the
+// Witches' potions are used, and last the kills are carried out, poisonings
+// first, sparing anyone who was saved.
+//
 // this assumes you will only ever have one night action...
 func (g *Game) processNightActions() {
-	slog.Info("processing night actions", "phase", g.Phase)
-	var nk *player.Player
+	slog.Info("processing night actions", "phase", g.phase)
+	blocked := map[*player.Player]bool{}
 	for _, fp := range g.nightActions {
+		if fp.From.Role.CanRoleblock() {
+			slog.Info("roleblocking", "from", fp.From, "to", fp.To)
+			blocked[fp.To] = true
+			g.record(&server.TimelineEvent{Type: server.Roleblocked, From: fp.From.ID, To: fp.To.ID})
+		}
+	}
+
+	for _, fp := range g.nightActions {
+		if blocked[fp.From] {
+			continue
+		}
 		var view *player.View
 		switch {
 		case fp.From.Role.CanViewForMax():
-			view = player.NewAttributeView(fp.To, role.MaxEvilAttribute, fp.To.Role.ViewForMaxEvil(), g.Phase)
-		case fp.From.Role.CanNightKill():
-			nk = fp.To
+			view = player.NewAttributeView(fp.To, role.MaxEvilAttribute, fp.To.Role.ViewForMaxEvil(), g.phase)
+		case fp.From.Role.CanNightKill(), fp.From.Role.CanSoloKill(), fp.From.Role.CanRoleblock():
+			// handled above, or below once every view is done
 		case fp.From.Role.CanViewForSeer():
-			view = player.NewAttributeView(fp.To, role.SeerAttribute, fp.To.Role.ViewForSeer(), g.Phase)
+			view = player.NewAttributeView(fp.To, role.SeerAttribute, fp.To.Role.ViewForSeer(), g.phase)
 		case fp.From.Role.CanViewForAux():
-			view = player.NewAttributeView(fp.To, role.AuxEvilAttribute, fp.To.Role.ViewForAuxEvil(), g.Phase)
+			view = player.NewAttributeView(fp.To, role.AuxEvilAttribute, fp.To.Role.ViewForAuxEvil(), g.phase)
 		default:
 			// TODO keep track of "most suspicious" (#4)
 		}
 		if view != nil {
+			g.recordView(fp.From, view)
 			fp.From.AddView(view)
 		}
 	}
 
-	// TODO this prioritizes the "last" wolf if there are multiple
-	if nk != nil {
-		g.KillPlayer(nk)
+	saved, poisonings := g.brewPotions(blocked)
+
+	// a hunter killed tonight shoots before the day starts
+	g.endingPhase = true
+
+	// poison goes first, so that poisoning the last wolf wins the game for
+	// the village even if the wolves' victim would have given them parity
+	kills := slices.Clone(poisonings)
+	// blocking the wolf whose choice counts stops the kill
+	if g.nightKill != nil && !blocked[g.nightKill.From] {
+		kills = append(kills, g.nightKill)
 	}
-	if g.state == Running {
-		g.nextPhase()
+	for _, fp := range g.nightActions {
+		if fp.From.Role.CanSoloKill() && !blocked[fp.From] {
+			kills = append(kills, fp)
+		}
 	}
+	for _, fp := range kills {
+		if g.state != Running {
+			break
+		}
+		if saved[fp.To] {
+			slog.Info("saved from night kill", "player", fp.To, "by", fp.From)
+			continue
+		}
+		cause := server.DeathCause(server.NightKilled)
+		if slices.Contains(poisonings, fp) {
+			cause = server.Poisoned
+		}
+		// the wolves and a killer may both choose the same victim
+		if fp.To.Role.Alive {
+			g.killPlayer(fp.To, cause)
+		}
+	}
+	g.moveOn()
 }
 
-func (g *Game) RevealPlayer(p *player.Player) {
-	slog.Info("revealing player", "player", p, "role", p.Role)
-	g.BroadcastView(player.NewRoleView(p, p.Role, g.Phase))
-}
-
-// BroadcastView sends a view to every player, alive and dead. It
+// broadcastView sends a view to every player, alive and dead. It
 // is primarily used for revealing the roles of dead players.
-func (g *Game) BroadcastView(v *player.View) {
-	for _, p := range g.Players {
+func (g *Game) broadcastView(v *player.View) {
+	for _, p := range g.players {
 		p.AddView(v)
 	}
 }
 
-func (g *Game) Broadcast(t server.MessageType, payload interface{}) {
-	for _, p := range g.Players {
+func (g *Game) broadcast(t server.MessageType, payload interface{}) {
+	for _, p := range g.players {
 		if err := p.Message(t, payload); err != nil {
 			slog.Error("error broadcasting message", "player", p, "error", err)
 		}
@@ -459,9 +1029,9 @@ func (g *Game) Broadcast(t server.MessageType, payload interface{}) {
 
 // probably needs to be better but hackathon
 func (g *Game) alivePlayerList() []*player.Player {
-	players := g.AlivePlayers
+	players := g.alivePlayers
 	if g.state == Setup {
-		players = g.Players
+		players = g.players
 	}
 	var list []*player.Player
 	for _, p := range players {
@@ -470,85 +1040,313 @@ func (g *Game) alivePlayerList() []*player.Player {
 	return list
 }
 
-func (g *Game) BroadcastPlayerList() {
-	g.Broadcast(server.AlivePlayerList, g.alivePlayerList())
+func (g *Game) broadcastPlayerList() {
+	g.broadcast(server.AlivePlayerList, g.alivePlayerList())
 }
 
+// ListenToGameChannel handles activities sent by players, one at a time,
+// until the game stops.
 func (g *Game) ListenToGameChannel() {
+	defer g.shutdown()
 	for {
 		slog.Info("waiting for message on game channel...")
-		activity := <-g.gameChannel
-
-		switch activity.Type {
-		case gamechannel.SetName:
-			g.BroadcastPlayerList()
-		case gamechannel.SetRoleset:
-			if err := g.ChooseRoleset(activity.Value.(string)); err != nil {
-				p, ok := g.Players[activity.From]
-				if ok {
-					slog.Warn("game: error setting roleset", "error", err)
-					p.Message(server.Error, err)
-				} else {
-					slog.Error("player not found in map?", "playerId", activity.From)
-				}
+		select {
+		case activity := <-g.gameChannel:
+			g.handle(activity)
+		case <-g.ctx.Done():
+			return
+		}
+	}
+}
+
+// shutdown closes the sockets of everyone still in the game.
+func (g *Game) shutdown() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	slog.Info("game stopped", "game", g.ID, "reason", context.Cause(g.ctx))
+	if g.stopTimer != nil {
+		g.stopTimer.Stop()
+	}
+	g.stopDayTimer()
+	g.stopShots()
+	for _, p := range g.players {
+		if err := p.Close(); err != nil {
+			slog.Warn("error closing player", "player", p, "error", err)
+		}
+	}
+}
+
+// stopAfter stops the game after d, replacing any stop already scheduled.
+func (g *Game) stopAfter(d time.Duration) {
+	if g.stopTimer != nil {
+		g.stopTimer.Stop()
+	}
+	g.stopTimer = time.AfterFunc(d, g.Close)
+}
+
+// checkAbandoned schedules the game to stop if nobody is connected to it, or
+// cancels that if somebody is.
+func (g *Game) checkAbandoned() {
+	for _, connected := range g.connected {
+		if connected {
+			if g.state != Finished && g.stopTimer != nil {
+				g.stopTimer.Stop()
+				g.stopTimer = nil
 			}
-		case gamechannel.Start:
-			if err := g.Start(); err != nil {
-				slog.Error("error starting", "error", err)
-				p, ok := g.Players[activity.From]
-				if ok {
-					p.Message(server.Error, err)
-				} else {
-					slog.Error("player not found in map?", "playerId", activity.From)
-				}
+			return
+		}
+	}
+	switch {
+	case len(g.players) == 0:
+		slog.Info("everyone left, stopping game", "game", g.ID)
+		g.cancel()
+	case g.state != Finished:
+		slog.Info("everyone disconnected, waiting for them to come back", "game", g.ID, "wait", g.AbandonAfter)
+		g.stopAfter(g.AbandonAfter)
+	}
+}
+
+func (g *Game) handle(activity *gamechannel.Activity) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch activity.Type {
+	case gamechannel.SetName:
+		p, ok := g.players[activity.From]
+		if !ok {
+			slog.Error("player not found in map?", "playerId", activity.From)
+			return
+		}
+		if err := g.setName(p, activity.Value.(string)); err != nil {
+			slog.Warn("game: error setting name", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.SetRoleset:
+		if err := g.chooseRoleset(activity.Value.(string)); err != nil {
+			slog.Warn("game: error setting roleset", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.Start:
+		if err := g.start(); err != nil {
+			slog.Error("error starting", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.Reconnect:
+		p, ok := g.players[activity.From]
+		if !ok {
+			slog.Error("unknown player reconnecting", "player", activity.From)
+			return
+		}
+		switch {
+		case g.substitutes[p.ID]:
+			delete(g.substitutes, p.ID)
+			p.Message(server.IDSet, p.ID)
+			p.Message(server.NameSet, p.Name)
+			g.joined = append(g.joined, p)
+			g.broadcast(server.PlayerSubstituted, p)
+		case g.vacant[p.ID]:
+			// they've come back to their own seat
+			delete(g.vacant, p.ID)
+			g.joined = append(g.joined, p)
+		}
+		g.connected[p.ID] = true
+		g.checkAbandoned()
+		p.Message(server.AlivePlayerList, g.alivePlayerList())
+		if g.leader == p && g.state == Setup {
+			slog.Info("sending roleset list to leader", "player", p)
+			g.sendLeaderMessages()
+		}
+		p.Message(server.LeaderSet, g.leader)
+		p.Message(server.LynchRuleSet, g.lynchRuleMessage())
+		p.Message(server.RevealModeSet, g.RevealMode)
+		if g.roleset != nil {
+			p.Message(server.RolesetSelected, g.roleset)
+		}
+		if g.state == Running {
+			p.Message(server.RoleAssigned, p.Role)
+			if len(g.voteHistory) > 0 {
+				p.Message(server.VoteHistory, g.voteHistory)
 			}
-		case gamechannel.Reconnect:
-			p, ok := g.Players[activity.From]
-			if !ok {
-				slog.Error("unknown player reconnecting", "player", activity.From)
-				continue
+			if g.isDay() {
+				p.Message(server.PhaseChanged, &server.Phase{Phase: server.Day, Count: g.phase})
+				g.updateTally()
+				p.Message(server.TallyChanged, g.tally)
+			} else {
+				p.Message(server.PhaseChanged, &server.Phase{Phase: server.Night, Count: g.phase})
 			}
-			p.Message(server.AlivePlayerList, g.alivePlayerList())
-			if g.Leader == p && g.state == Setup {
-				slog.Info("sending roleset list to leader", "player", p)
-				g.SendLeaderMessages()
+			for _, v := range p.Views {
+				p.Message(server.View, v)
 			}
-			p.Message(server.LeaderSet, g.Leader)
-			if g.Roleset != nil {
-				p.Message(server.RolesetSelected, g.Roleset)
+			if p.Role != nil && !p.Role.Alive {
+				p.Message(server.PlayerKilled, nil)
 			}
-			if g.state == Running {
-				p.Message(server.RoleAssigned, p.Role)
-				if g.IsDay() {
-					p.Message(server.PhaseChanged, &server.Phase{Phase: server.Day, Count: g.Phase})
-					p.Message(server.TallyChanged, g.Tally)
-				} else {
-					p.Message(server.PhaseChanged, &server.Phase{Phase: server.Night, Count: g.Phase})
-				}
-				for _, v := range p.Views {
-					p.Message(server.View, v)
-				}
-				if p.Role != nil && !p.Role.Alive {
-					p.Message(server.PlayerKilled, nil)
-				}
-			} else if g.state == Finished {
-				g.Broadcast(server.GameOver, g.ToGameOverMessage())
+			if len(g.shots) > 0 {
+				p.Message(server.RevengeShot, g.shotPrompt())
+			}
+		} else if g.state == Finished {
+			g.broadcast(server.GameOver, g.toGameOverMessage())
+		}
+	case gamechannel.Vote:
+		fp, err := g.fingerPoint(activity)
+		if err == nil {
+			err = g.vote(fp)
+		}
+		if err != nil {
+			slog.Warn("game: error voting", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.NightAction:
+		fp, err := g.fingerPoint(activity)
+		if err == nil {
+			err = g.setNightAction(fp)
+		}
+		if err != nil {
+			slog.Warn("game: error setting night action", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.Quit:
+		p, ok := g.players[activity.From]
+		if !ok {
+			return
+		}
+		if g.state == Running {
+			g.quitMidGame(p)
+		} else {
+			g.removePlayer(p)
+			p.Close()
+		}
+		g.checkAbandoned()
+	case gamechannel.TransferLeader:
+		fp, err := g.fingerPoint(activity)
+		if err == nil {
+			err = g.transferLeader(fp)
+		}
+		if err != nil {
+			slog.Warn("game: error transferring leadership", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.Kick:
+		fp, err := g.fingerPoint(activity)
+		if err == nil {
+			err = g.kick(fp)
+		}
+		if err != nil {
+			slog.Warn("game: error kicking", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.SetLynchRule:
+		p, ok := g.players[activity.From]
+		if !ok {
+			g.reportError(activity, &UnknownPlayerError{ID: activity.From})
+			return
+		}
+		payload := activity.Value.(*client.SetLynchRulePayload)
+		if err := g.setLynchRule(p, LynchRule(payload.Rule), time.Duration(payload.DayLength)*time.Second); err != nil {
+			slog.Warn("game: error setting lynch rule", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.SetRevealMode:
+		p, ok := g.players[activity.From]
+		if !ok {
+			g.reportError(activity, &UnknownPlayerError{ID: activity.From})
+			return
+		}
+		if err := g.setRevealMode(p, RevealMode(activity.Value.(string))); err != nil {
+			slog.Warn("game: error setting reveal mode", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.Shoot:
+		fp, err := g.fingerPoint(activity)
+		if err == nil {
+			err = g.shoot(fp)
+		}
+		if err != nil {
+			slog.Warn("game: error shooting", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.ChooseLovers:
+		p, ok := g.players[activity.From]
+		if !ok {
+			g.reportError(activity, &UnknownPlayerError{ID: activity.From})
+			return
+		}
+		payload := activity.Value.(*client.LoversPayload)
+		a, ok := g.players[payload.First]
+		if !ok {
+			g.reportError(activity, &UnknownPlayerError{ID: payload.First})
+			return
+		}
+		b, ok := g.players[payload.Second]
+		if !ok {
+			g.reportError(activity, &UnknownPlayerError{ID: payload.Second})
+			return
+		}
+		if err := g.chooseLovers(p, a, b); err != nil {
+			slog.Warn("game: error choosing lovers", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.UsePotion:
+		p, ok := g.players[activity.From]
+		if !ok {
+			g.reportError(activity, &UnknownPlayerError{ID: activity.From})
+			return
+		}
+		payload := activity.Value.(*client.PotionPayload)
+		var target *player.Player
+		if payload.Target != uuid.Nil {
+			if target, ok = g.players[payload.Target]; !ok {
+				g.reportError(activity, &UnknownPlayerError{ID: payload.Target})
+				return
 			}
-		case gamechannel.Vote:
-			from := g.Players[activity.From]
-			to := g.Players[activity.Value.(uuid.UUID)]
-			g.Vote(&player.FingerPoint{From: from, To: to})
-		case gamechannel.NightAction:
-			from := g.Players[activity.From]
-			to := g.Players[activity.Value.(uuid.UUID)]
-			g.SetNightAction(&player.FingerPoint{From: from, To: to})
-		case gamechannel.Quit:
-			p := g.Players[activity.From]
-			delete(g.Players, activity.From)
-			g.Broadcast(server.PlayerLeave, p)
-			g.BroadcastPlayerList()
-		case gamechannel.Awoo:
-			g.Broadcast(server.Awoo, "awooooooooo")
 		}
+		if err := g.choosePotion(p, Potion(payload.Potion), target); err != nil {
+			slog.Warn("game: error using potion", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.Disconnect:
+		if _, ok := g.players[activity.From]; !ok {
+			return
+		}
+		g.connected[activity.From] = false
+		g.checkAbandoned()
+	case gamechannel.ResetGame:
+		p, ok := g.players[activity.From]
+		if !ok {
+			g.reportError(activity, &UnknownPlayerError{ID: activity.From})
+			return
+		}
+		if err := g.reset(p); err != nil {
+			slog.Warn("game: error resetting", "error", err)
+			g.reportError(activity, err)
+		}
+	case gamechannel.Awoo:
+		g.broadcast(server.Awoo, "awooooooooo")
+	}
+}
+
+// fingerPoint builds a FingerPoint from an activity whose value is the ID of
+// the targeted player.
+func (g *Game) fingerPoint(activity *gamechannel.Activity) (*player.FingerPoint, error) {
+	from, ok := g.players[activity.From]
+	if !ok {
+		return nil, &UnknownPlayerError{ID: activity.From}
+	}
+	id := activity.Value.(uuid.UUID)
+	to, ok := g.players[id]
+	if !ok {
+		return nil, &UnknownPlayerError{ID: id}
+	}
+	return &player.FingerPoint{From: from, To: to}, nil
+}
+
+// reportError sends an error back to the player whose activity caused it.
+func (g *Game) reportError(activity *gamechannel.Activity, err error) {
+	p, ok := g.players[activity.From]
+	if !ok {
+		slog.Error("player not found in map?", "playerId", activity.From)
+		return
+	}
+	if sendErr := p.SendError(activity.RequestID, err); sendErr != nil {
+		slog.Error("error sending error", "player", p, "error", sendErr)
 	}
 }
@@ -1,8 +1,10 @@
 package game
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/awoo-detat/werewolf/gamechannel/server"
 	"github.com/awoo-detat/werewolf/player"
 	"github.com/awoo-detat/werewolf/role"
 	"github.com/awoo-detat/werewolf/role/roleset"
@@ -17,11 +19,11 @@ func TestInitialization(t *testing.T) {
 	g := NewGame(l)
 
 	assert.NotEmpty(g.ID)
-	assert.Equal(l, g.Leader)
-	assert.Len(g.Players, 1)
-	assert.Nil(g.Roleset)
+	assert.Equal(l, g.Snapshot().Leader)
+	assert.Len(g.Snapshot().Players, 1)
+	assert.Nil(g.Snapshot().Roleset)
 	assert.Equal(Setup, g.State())
-	assert.Equal(0, g.Phase)
+	assert.Equal(0, g.Snapshot().Phase)
 }
 
 func TestAddingPlayers(t *testing.T) {
@@ -31,7 +33,7 @@ func TestAddingPlayers(t *testing.T) {
 
 	g.AddPlayer(p)
 
-	assert.Len(t, g.Players, 2)
+	assert.Len(t, g.Snapshot().Players, 2)
 }
 
 func TestSetRoleset(t *testing.T) {
@@ -42,7 +44,7 @@ func TestSetRoleset(t *testing.T) {
 
 	err := g.ChooseRoleset("Vanilla Fiver")
 
-	assert.Equal(rs, g.Roleset)
+	assert.Equal(rs, g.Snapshot().Roleset)
 	assert.Nil(err)
 }
 
@@ -53,7 +55,7 @@ func TestRolesetMustExist(t *testing.T) {
 
 	err := g.ChooseRoleset("dkjjfkfwegfwegy")
 
-	assert.Nil(g.Roleset)
+	assert.Nil(g.Snapshot().Roleset)
 	assert.Error(err)
 }
 
@@ -169,11 +171,11 @@ func TestRolesAreAssignedAtGameStart(t *testing.T) {
 	assert.Equal(Running, g.State())
 
 	assignedRoles := []*role.Role{}
-	for _, p := range g.Players {
+	for _, p := range g.Snapshot().Players {
 		assert.NotNil(p.Role)
 		assignedRoles = append(assignedRoles, p.Role)
 	}
-	assert.ElementsMatch(g.Roleset.Roles, assignedRoles)
+	assert.ElementsMatch(g.Snapshot().Roleset.Roles, assignedRoles)
 }
 
 // really more of an integration test, maybe!
@@ -200,7 +202,7 @@ func TestGame(t *testing.T) {
 	var wolf1, wolf2, sorcerer, hunter, seer, v1, v2, v3, v4, v5, v6 *player.Player
 
 	// assign each role to a known variable
-	for _, p := range g.Players {
+	for _, p := range g.Snapshot().Players {
 		if p.Role.IsMaxEvil() {
 			if wolf1 == nil {
 				wolf1 = p
@@ -289,7 +291,7 @@ func TestGame(t *testing.T) {
 		assert.Empty(v6.Views)
 
 		// now it's day!
-		assert.Equal(1, g.Phase)
+		assert.Equal(1, g.Snapshot().Phase)
 		assert.Equal(Running, g.State())
 		assert.True(g.IsDay())
 		assert.False(g.IsNight())
@@ -298,7 +300,7 @@ func TestGame(t *testing.T) {
 	t.Run("D1, villager dies", func(t *testing.T) {
 		assert.Equal(2, len(g.AliveMaxEvils()))
 		assert.True(g.IsDay())
-		assert.Equal(1, g.Phase)
+		assert.Equal(1, g.Snapshot().Phase)
 		// seer can do a view already!
 		assert.Nil(g.SetNightAction(&player.FingerPoint{From: seer, To: v1}))
 
@@ -315,23 +317,23 @@ func TestGame(t *testing.T) {
 		assert.Nil(g.Vote(&player.FingerPoint{From: v6, To: v5}))
 
 		assert.True(g.IsDay())
-		assert.Equal(1, g.Phase)
+		assert.Equal(1, g.Snapshot().Phase)
 
 		assert.Nil(g.Vote(&player.FingerPoint{From: seer, To: v1})) // 5/6
 		assert.True(g.IsDay())
-		assert.Equal(1, g.Phase)
+		assert.Equal(1, g.Snapshot().Phase)
 
 		assert.Nil(g.Vote(&player.FingerPoint{From: hunter, To: v1})) // 6/6
 		assert.True(g.IsNight())
-		assert.Equal(2, g.Phase)
+		assert.Equal(2, g.Snapshot().Phase)
 		assert.False(v1.Role.Alive)
 
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(v1, v.Player)
 			assert.Equal(v1.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase-1, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase-1, v.GamePhase)
 		}
 	})
 
@@ -345,22 +347,22 @@ func TestGame(t *testing.T) {
 		assert.Nil(g.SetNightAction(&player.FingerPoint{From: seer, To: v2}))
 		assert.Error(g.SetNightAction(&player.FingerPoint{From: v1, To: wolf1}))
 		assert.Error(g.SetNightAction(&player.FingerPoint{From: sorcerer, To: v1}))
-		// sure why not have fun
-		assert.Nil(g.SetNightAction(&player.FingerPoint{From: v3, To: v2}))
+		// villagers have no night action to take
+		assert.Error(g.SetNightAction(&player.FingerPoint{From: v3, To: v2}))
 		assert.True(g.IsNight())
-		assert.Equal(2, g.Phase)
+		assert.Equal(2, g.Snapshot().Phase)
 
 		assert.Nil(g.SetNightAction(&player.FingerPoint{From: sorcerer, To: hunter}))
 		assert.True(g.IsDay())
-		assert.Equal(3, g.Phase)
+		assert.Equal(3, g.Snapshot().Phase)
 
 		assert.False(v3.Role.Alive)
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(v3, v.Player)
 			assert.Equal(v3.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase-1, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase-1, v.GamePhase)
 		}
 
 		// no new views
@@ -394,12 +396,14 @@ func TestGame(t *testing.T) {
 
 	t.Run("D2, villager dies", func(t *testing.T) {
 		// new day!
-		assert.Empty(g.Tally.List[0].Votes)
+		g.mu.Lock()
+		assert.Empty(g.tally.List[0].Votes)
+		g.mu.Unlock()
 		assert.Empty(g.nightActions)
 		assert.Equal(2, len(g.AliveMaxEvils()))
 		assert.True(g.IsDay())
-		assert.Equal(3, g.Phase)
-		assert.Equal(9, len(g.AlivePlayers))
+		assert.Equal(3, g.Snapshot().Phase)
+		assert.Equal(9, len(g.Snapshot().AlivePlayers))
 
 		assert.Error(g.Vote(&player.FingerPoint{From: wolf1, To: v1}))
 		assert.Error(g.Vote(&player.FingerPoint{From: v1, To: wolf1}))
@@ -416,14 +420,14 @@ func TestGame(t *testing.T) {
 		assert.Nil(g.Vote(&player.FingerPoint{From: hunter, To: v2})) // 5/5
 
 		assert.True(g.IsNight())
-		assert.Equal(4, g.Phase)
+		assert.Equal(4, g.Snapshot().Phase)
 		assert.False(v2.Role.Alive)
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(v2, v.Player)
 			assert.Equal(v2.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase-1, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase-1, v.GamePhase)
 		}
 	})
 
@@ -434,15 +438,15 @@ func TestGame(t *testing.T) {
 		assert.Nil(g.SetNightAction(&player.FingerPoint{From: sorcerer, To: seer}))
 
 		assert.True(g.IsDay())
-		assert.Equal(5, g.Phase)
+		assert.Equal(5, g.Snapshot().Phase)
 
 		assert.False(v4.Role.Alive)
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(v4, v.Player)
 			assert.Equal(v4.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase-1, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase-1, v.GamePhase)
 		}
 
 		// no new views
@@ -474,12 +478,14 @@ func TestGame(t *testing.T) {
 	})
 
 	t.Run("D3, wolf dies", func(t *testing.T) {
-		assert.Empty(g.Tally.List[0].Votes)
+		g.mu.Lock()
+		assert.Empty(g.tally.List[0].Votes)
+		g.mu.Unlock()
 		assert.Empty(g.nightActions)
 		assert.Equal(2, len(g.AliveMaxEvils()))
 		assert.True(g.IsDay())
-		assert.Equal(5, g.Phase)
-		assert.Equal(7, len(g.AlivePlayers))
+		assert.Equal(5, g.Snapshot().Phase)
+		assert.Equal(7, len(g.Snapshot().AlivePlayers))
 
 		assert.Nil(g.Vote(&player.FingerPoint{From: wolf1, To: seer}))
 		assert.Nil(g.Vote(&player.FingerPoint{From: wolf2, To: wolf1})) // 1/4
@@ -488,19 +494,19 @@ func TestGame(t *testing.T) {
 		assert.Nil(g.Vote(&player.FingerPoint{From: seer, To: wolf1}))   // 3/4
 
 		assert.True(g.IsDay())
-		assert.Equal(5, g.Phase)
+		assert.Equal(5, g.Snapshot().Phase)
 		assert.Nil(g.Vote(&player.FingerPoint{From: v5, To: wolf1}))
 		// didn't even need v6
 
 		assert.True(g.IsNight())
-		assert.Equal(6, g.Phase)
+		assert.Equal(6, g.Snapshot().Phase)
 		assert.False(wolf1.Role.Alive)
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(wolf1, v.Player)
 			assert.Equal(wolf1.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase-1, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase-1, v.GamePhase)
 		}
 	})
 
@@ -511,15 +517,15 @@ func TestGame(t *testing.T) {
 		assert.Nil(g.SetNightAction(&player.FingerPoint{From: sorcerer, To: seer}))
 
 		assert.True(g.IsDay())
-		assert.Equal(7, g.Phase)
+		assert.Equal(7, g.Snapshot().Phase)
 
 		assert.False(seer.Role.Alive)
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(seer, v.Player)
 			assert.Equal(seer.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase-1, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase-1, v.GamePhase)
 		}
 
 		// no new views
@@ -552,30 +558,32 @@ func TestGame(t *testing.T) {
 	})
 
 	t.Run("D4, sorc dies", func(t *testing.T) {
-		assert.Empty(g.Tally.List[0].Votes)
+		g.mu.Lock()
+		assert.Empty(g.tally.List[0].Votes)
+		g.mu.Unlock()
 		assert.Empty(g.nightActions)
 		assert.Equal(1, len(g.AliveMaxEvils()))
 		assert.True(g.IsDay())
-		assert.Equal(7, g.Phase)
-		assert.Equal(5, len(g.AlivePlayers))
+		assert.Equal(7, g.Snapshot().Phase)
+		assert.Equal(5, len(g.Snapshot().AlivePlayers))
 
 		assert.Nil(g.Vote(&player.FingerPoint{From: wolf2, To: hunter}))
 		assert.Nil(g.Vote(&player.FingerPoint{From: sorcerer, To: hunter}))
 		assert.Nil(g.Vote(&player.FingerPoint{From: hunter, To: sorcerer})) // 1/3
 		assert.Nil(g.Vote(&player.FingerPoint{From: v5, To: sorcerer}))     // 2/3
 		assert.True(g.IsDay())
-		assert.Equal(7, g.Phase)
+		assert.Equal(7, g.Snapshot().Phase)
 		assert.Nil(g.Vote(&player.FingerPoint{From: v6, To: sorcerer})) // 3/3
 
 		assert.True(g.IsNight())
-		assert.Equal(8, g.Phase)
+		assert.Equal(8, g.Snapshot().Phase)
 		assert.False(sorcerer.Role.Alive)
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(sorcerer, v.Player)
 			assert.Equal(sorcerer.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase-1, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase-1, v.GamePhase)
 		}
 	})
 
@@ -583,15 +591,15 @@ func TestGame(t *testing.T) {
 		assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf2, To: v5}))
 
 		assert.True(g.IsDay())
-		assert.Equal(9, g.Phase)
+		assert.Equal(9, g.Snapshot().Phase)
 
 		assert.False(v5.Role.Alive)
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(v5, v.Player)
 			assert.Equal(v5.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase-1, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase-1, v.GamePhase)
 		}
 
 		// no new views
@@ -610,30 +618,52 @@ func TestGame(t *testing.T) {
 	})
 
 	t.Run("D5, villager dies, good wins by hunter victory", func(t *testing.T) {
-		assert.Empty(g.Tally.List[0].Votes)
+		g.mu.Lock()
+		assert.Empty(g.tally.List[0].Votes)
+		g.mu.Unlock()
 		assert.Empty(g.nightActions)
 		assert.Equal(1, len(g.AliveMaxEvils()))
 		assert.True(g.IsDay())
-		assert.Equal(9, g.Phase)
-		assert.Equal(3, len(g.AlivePlayers))
+		assert.Equal(9, g.Snapshot().Phase)
+		assert.Equal(3, len(g.Snapshot().AlivePlayers))
 
 		assert.Nil(g.Vote(&player.FingerPoint{From: wolf2, To: v6})) // 1/2 needed
 		assert.Nil(g.Vote(&player.FingerPoint{From: v6, To: hunter}))
 		assert.True(g.IsDay())
-		assert.Equal(9, g.Phase)
+		assert.Equal(9, g.Snapshot().Phase)
 		assert.Nil(g.Vote(&player.FingerPoint{From: hunter, To: v6})) // 2/2
 
 		assert.False(v6.Role.Alive)
-		for _, p := range g.Players {
+		for _, p := range g.Snapshot().Players {
 			v := p.Views[len(p.Views)-1]
 			assert.Equal(v6, v.Player)
 			assert.Equal(v6.Role, v.Role)
 			assert.True(v.Hit)
-			assert.Equal(g.Phase, v.GamePhase)
+			assert.Equal(g.Snapshot().Phase, v.GamePhase)
 		}
 		assert.True(wolf2.Role.Alive)
 		assert.Len(g.AliveMaxEvils(), 1, "didn't need to kill all wolves")
 		assert.Equal(Finished, g.State())
-		assert.Equal(role.Good, g.Winner)
+		assert.Equal(role.Good, g.Snapshot().Winner)
 	})
 }
+
+func TestVillagersHaveNoNightAction(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	lynch(t, g, villagers)
+
+	// a villager's choice is refused, and doesn't stand in for the wolf's
+	err := g.SetNightAction(&player.FingerPoint{From: villagers[1], To: villagers[2]})
+	var noAction *NoNightActionError
+	assert.True(errors.As(err, &noAction))
+	assert.Equal("noNightAction", server.NewErrorMessage("", err).Code)
+	assert.True(g.IsNight())
+	for _, e := range g.Timeline() {
+		assert.False(e.Type == server.NightAction && e.From == villagers[1].ID)
+	}
+
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[2]}))
+	assert.True(g.IsDay())
+	assert.False(villagers[2].Role.Alive)
+}
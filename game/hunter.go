@@ -0,0 +1,146 @@
+package game
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+)
+
+// DefaultShotTime is how long a hunter has to take their revenge shot,
+// unless the game says otherwise.
+const DefaultShotTime = 30 * time.Second
+
+// A pendingShot is a dead hunter the game is waiting on. The clock only
+// starts once the shots before theirs have been taken.
+type pendingShot struct {
+	hunter   *player.Player
+	deadline time.Time
+	timer    *time.Timer
+}
+
+// holdForShot keeps the game from moving on until p, a hunter who has just
+// died, takes their shot or runs out of time. Hunters who die together, ie
+// to the wolves and a killer on the same night, shoot in the order they died.
+func (g *Game) holdForShot(p *player.Player) {
+	slog.Info("waiting for revenge shot", "hunter", p, "wait", g.ShotTime, "queued", len(g.shots))
+	g.shots = append(g.shots, &pendingShot{hunter: p})
+	g.startShot()
+}
+
+// startShot starts the clock on the first shot in the queue, if it isn't
+// running already, and tells everyone whose turn it is.
+func (g *Game) startShot() {
+	if len(g.shots) == 0 || g.shots[0].timer != nil {
+		return
+	}
+	shot := g.shots[0]
+	shot.deadline = time.Now().Add(g.ShotTime)
+	shot.timer = time.AfterFunc(g.ShotTime, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.ctx.Err() != nil || len(g.shots) == 0 || g.shots[0] != shot {
+			return
+		}
+		slog.Info("revenge shot not taken in time", "hunter", shot.hunter)
+		g.resolveShot(nil)
+	})
+	g.broadcast(server.RevengeShot, g.shotPrompt())
+}
+
+// shooter is the hunter whose shot the game is waiting on, if any.
+func (g *Game) shooter() *player.Player {
+	if len(g.shots) == 0 {
+		return nil
+	}
+	return g.shots[0].hunter
+}
+
+func (g *Game) shotPrompt() *server.RevengeShotPrompt {
+	return &server.RevengeShotPrompt{Hunter: g.shots[0].hunter.ID, Deadline: g.shots[0].deadline}
+}
+
+// shoot takes fp.From's revenge shot at fp.To.
+func (g *Game) shoot(fp *player.FingerPoint) error {
+	if g.state != Running {
+		return &StateError{NeedState: Running, InState: g.state}
+	}
+	if fp == nil || fp.From == nil || fp.To == nil {
+		return &FingerPointError{FingerPoint: fp}
+	}
+	if g.shooter() != fp.From {
+		return &NotShooterError{Player: fp.From}
+	}
+	if !fp.To.Role.Alive {
+		return &DeadPlayerError{Player: fp.To, Action: "be shot"}
+	}
+	g.resolveShot(fp.To)
+	return nil
+}
+
+// resolveShot kills target, if the first hunter in the queue chose one. Once
+// no shots are left, the game carries on from where they held it.
+func (g *Game) resolveShot(target *player.Player) {
+	shot := g.shots[0]
+	shot.timer.Stop()
+	g.shots = g.shots[1:]
+	if target != nil {
+		slog.Info("revenge shot", "hunter", shot.hunter, "target", target)
+		g.record(&server.TimelineEvent{Type: server.HunterShot, From: shot.hunter.ID, To: target.ID})
+		// if they shoot another hunter, that hunter joins the queue
+		g.killPlayer(target, server.Shot)
+	}
+	if g.state != Running {
+		return
+	}
+	if len(g.shots) > 0 {
+		g.startShot()
+		return
+	}
+	g.afterShots()
+}
+
+// afterShots carries on once every revenge shot has been taken. If the
+// hunters died as the phase ended, the game moves on as it would have
+// without them; if they died partway through it, ie a lover of someone who
+// quit, the phase goes on.
+func (g *Game) afterShots() {
+	if g.endingPhase {
+		// moveOn does the win check the hunters' deaths were held for
+		g.moveOn()
+		return
+	}
+	g.checkForWin()
+	if g.state != Running {
+		return
+	}
+	if g.isDay() && !g.deadline.IsZero() && !time.Now().Before(g.deadline) {
+		// the deadline passed while the shots were being taken
+		g.endDayAtDeadline(g.phase)
+		return
+	}
+	g.resume()
+}
+
+// dropShot takes p's shot out of the queue, ie because they quit before
+// taking it.
+func (g *Game) dropShot(p *player.Player) {
+	i := slices.IndexFunc(g.shots, func(s *pendingShot) bool { return s.hunter == p })
+	switch {
+	case i == 0:
+		g.resolveShot(nil)
+	case i > 0:
+		g.shots = slices.Delete(g.shots, i, i+1)
+	}
+}
+
+func (g *Game) stopShots() {
+	for _, s := range g.shots {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+	}
+	g.shots = nil
+}
@@ -0,0 +1,185 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/win"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revengeFiver starts a Vanilla Fiver in which the last villager is a
+// Revenge Hunter.
+func revengeFiver(t *testing.T) (*Game, *player.Player, []*player.Player) {
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	g.mu.Lock()
+	villagers[3].Role = role.RevengeHunter()
+	g.mu.Unlock()
+	return g, wolf, villagers
+}
+
+func TestRevengeShot(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := revengeFiver(t)
+	hunter := villagers[3]
+
+	for _, p := range villagers[:3] {
+		assert.Nil(g.Vote(&player.FingerPoint{From: p, To: hunter}))
+	}
+	assert.False(hunter.Role.Alive)
+	assert.True(g.IsDay(), "the day waits for the shot")
+
+	var waiting *WaitingForShotError
+	assert.True(errors.As(g.Vote(&player.FingerPoint{From: wolf, To: villagers[0]}), &waiting))
+	var notShooter *NotShooterError
+	assert.True(errors.As(g.Shoot(&player.FingerPoint{From: villagers[0], To: wolf}), &notShooter))
+
+	assert.Nil(g.Shoot(&player.FingerPoint{From: hunter, To: wolf}))
+	assert.False(wolf.Role.Alive)
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+
+	var shot *server.TimelineEvent
+	for _, e := range g.Timeline() {
+		if e.Type == server.HunterShot {
+			shot = e
+		}
+	}
+	if assert.NotNil(shot) {
+		assert.Equal(hunter.ID, shot.From)
+		assert.Equal(wolf.ID, shot.To)
+	}
+}
+
+func TestRevengeShotChangesWinner(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := revengeFiver(t)
+	hunter := villagers[3]
+	lynch(t, g, villagers)
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[1]}))
+
+	// losing the hunter would leave the wolf at parity, but they shoot first
+	assert.Nil(g.Vote(&player.FingerPoint{From: wolf, To: hunter}))
+	assert.Nil(g.Vote(&player.FingerPoint{From: villagers[2], To: hunter}))
+	assert.Equal(Running, g.State())
+	assert.Nil(g.Shoot(&player.FingerPoint{From: hunter, To: wolf}))
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+}
+
+func TestRevengeShotTimesOut(t *testing.T) {
+	g, wolf, villagers := revengeFiver(t)
+	hunter := villagers[3]
+	g.mu.Lock()
+	g.ShotTime = 10 * time.Millisecond
+	g.mu.Unlock()
+	lynch(t, g, villagers)
+
+	// without a shot, the night ends and nobody else dies
+	assert.Nil(t, g.SetNightAction(&player.FingerPoint{From: wolf, To: hunter}))
+	assert.Eventually(t, g.IsDay, time.Second, time.Millisecond)
+	assert.Equal(t, Running, g.State())
+	assert.Len(t, g.Snapshot().AlivePlayers, 3)
+}
+
+func TestQuittingHunterHasNoShot(t *testing.T) {
+	g, _, villagers := revengeFiver(t)
+	quit(g, villagers[3])
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	assert.Empty(t, g.shots)
+	assert.True(t, g.isDay())
+}
+
+func TestKillPlayerCause(t *testing.T) {
+	assert := assert.New(t)
+	g, _, villagers := revengeFiver(t)
+	hunter := villagers[3]
+
+	// a mod-kill takes the hunter without a shot
+	g.KillPlayer(hunter, server.ModKilled)
+	assert.False(hunter.Role.Alive)
+	g.mu.Lock()
+	assert.Empty(g.shots)
+	g.mu.Unlock()
+
+	g, _, villagers = revengeFiver(t)
+	hunter = villagers[3]
+	g.KillPlayer(hunter, server.NightKilled)
+	g.mu.Lock()
+	assert.Equal(hunter, g.shooter())
+	g.mu.Unlock()
+}
+
+func TestHunterDiesMidDay(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := revengeFiver(t)
+	hunter := villagers[3]
+
+	// nothing was ending the day, so it goes on once they've shot
+	g.KillPlayer(hunter, server.NightKilled)
+	assert.Nil(g.Shoot(&player.FingerPoint{From: hunter, To: villagers[0]}))
+	assert.False(villagers[0].Role.Alive)
+	assert.Equal(Running, g.State())
+	assert.Equal(1, g.Snapshot().Phase)
+
+	voteOut(t, g, wolf, villagers[1], villagers[2])
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+}
+
+func TestHuntersShootInTurn(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	killer, first, second := villagers[0], villagers[2], villagers[3]
+	g.mu.Lock()
+	killer.Role = role.SerialKiller()
+	first.Role = role.RevengeHunter()
+	second.Role = role.RevengeHunter()
+	g.roleset.Win = win.Loners{Then: win.Parity{}}
+	g.mu.Unlock()
+
+	voteOut(t, g, villagers[1], killer, first, second)
+	require.Nil(t, g.SetNightAction(&player.FingerPoint{From: wolf, To: first}))
+	require.Nil(t, g.SetNightAction(&player.FingerPoint{From: killer, To: second}))
+	assert.False(first.Role.Alive)
+	assert.False(second.Role.Alive)
+	assert.True(g.IsNight(), "the night waits for both shots")
+
+	var notShooter *NotShooterError
+	assert.True(errors.As(g.Shoot(&player.FingerPoint{From: second, To: killer}), &notShooter), "it isn't their turn yet")
+	assert.Nil(g.Shoot(&player.FingerPoint{From: first, To: wolf}))
+	assert.Equal(Running, g.State())
+	assert.Nil(g.Shoot(&player.FingerPoint{From: second, To: killer}))
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+}
+
+func TestDeadlinePassesDuringShot(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := lynchFiver(t, PluralityAtDeadline, 20*time.Millisecond)
+	hunter := villagers[3]
+	g.mu.Lock()
+	hunter.Role = role.RevengeHunter()
+	g.mu.Unlock()
+
+	assert.Nil(g.Vote(&player.FingerPoint{From: villagers[1], To: villagers[0]}))
+	g.KillPlayer(hunter, server.NightKilled)
+	time.Sleep(50 * time.Millisecond)
+	assert.True(g.IsDay(), "the day waits for the shot")
+	assert.True(villagers[0].Role.Alive)
+
+	// once it's taken, the day ends as the deadline said it should
+	assert.Nil(g.Shoot(&player.FingerPoint{From: hunter, To: villagers[2]}))
+	assert.False(villagers[0].Role.Alive)
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Evil), g.Snapshot().Winner)
+	assert.True(wolf.Role.Alive)
+}
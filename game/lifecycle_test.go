@@ -0,0 +1,117 @@
+package game
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// playingGame starts a five player game with each player's Play running, as
+// the hub would.
+func playingGame(t *testing.T) (*Game, []*player.MockCommunicator) {
+	comms := []*player.MockCommunicator{}
+	players := []*player.Player{}
+	for i := 0; i < 5; i++ {
+		c := player.NewMockCommunicator()
+		comms = append(comms, c)
+		players = append(players, player.NewPlayer(c))
+	}
+	g := NewGame(players[0])
+	g.Linger = time.Millisecond
+	g.AbandonAfter = time.Millisecond
+	for _, p := range players[1:] {
+		g.AddPlayer(p)
+	}
+	for _, p := range players {
+		go p.Play()
+	}
+	assert.Nil(t, g.ChooseRoleset("Vanilla Fiver"))
+	assert.Nil(t, g.Start())
+	return g, comms
+}
+
+// assertStopped checks that the game stopped, closed every socket, and left
+// no goroutines behind.
+func assertStopped(t *testing.T, g *Game, comms []*player.MockCommunicator, goroutines int) {
+	select {
+	case <-g.Done():
+	case <-time.After(time.Second):
+		assert.FailNow(t, "game did not stop")
+	}
+	assert.Eventually(t, func() bool {
+		for _, c := range comms {
+			if !c.Closed() {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+
+	// polled here rather than with Eventually, whose checks run in their own
+	// goroutines
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines, "goroutines leaked")
+}
+
+func TestFinishedGameStops(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	g, comms := playingGame(t)
+
+	g.EndGame(role.Good)
+	assertStopped(t, g, comms, goroutines)
+}
+
+func TestAbandonedGameStops(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	g, comms := playingGame(t)
+
+	// everyone drops without quitting
+	for _, c := range comms {
+		c.Close()
+	}
+	assertStopped(t, g, comms, goroutines)
+	assert.Equal(t, Running, g.State())
+}
+
+func TestReconnectingKeepsGameRunning(t *testing.T) {
+	g, comms := playingGame(t)
+	g.mu.Lock()
+	g.AbandonAfter = time.Hour
+	g.mu.Unlock()
+	for _, c := range comms {
+		c.Close()
+	}
+	assert.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.stopTimer != nil
+	}, time.Second, time.Millisecond)
+
+	s := g.Snapshot()
+	s.Players[0].Replace(player.NewMockCommunicator())
+	assert.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.stopTimer == nil
+	}, time.Second, time.Millisecond)
+
+	g.Close()
+	<-g.Done()
+}
+
+func TestClosingStopsGame(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	g, comms := playingGame(t)
+
+	g.Close()
+	g.Close()
+	assertStopped(t, g, comms, goroutines)
+}
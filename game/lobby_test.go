@@ -0,0 +1,128 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel"
+	"github.com/awoo-detat/werewolf/player"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func lobby(count int) (*Game, []*player.Player) {
+	players := []*player.Player{}
+	for i := 0; i < count; i++ {
+		p := player.NewPlayer(player.NewMockCommunicator())
+		p.Addr = "192.0.2." + string(rune('1'+i))
+		players = append(players, p)
+	}
+	g := NewGame(players[0])
+	for _, p := range players[1:] {
+		g.AddPlayer(p)
+	}
+	return g, players
+}
+
+func TestLeaderSucceedsOnQuit(t *testing.T) {
+	g, players := lobby(3)
+	assert.Equal(t, players[0], g.Snapshot().Leader)
+
+	g.gameChannel <- &gamechannel.Activity{Type: gamechannel.Quit, From: players[0].ID}
+	assert.Eventually(t, func() bool { return g.Snapshot().Leader == players[1] }, time.Second, time.Millisecond)
+
+	// disconnected players are passed over
+	g.gameChannel <- &gamechannel.Activity{Type: gamechannel.Disconnect, From: players[2].ID}
+	g.gameChannel <- &gamechannel.Activity{Type: gamechannel.Quit, From: players[1].ID}
+	assert.Eventually(t, func() bool { return g.Snapshot().Leader == players[2] }, time.Second, time.Millisecond)
+}
+
+func TestTransferLeader(t *testing.T) {
+	assert := assert.New(t)
+	g, players := lobby(3)
+
+	var notLeader *NotLeaderError
+	err := g.TransferLeader(&player.FingerPoint{From: players[1], To: players[1]})
+	assert.True(errors.As(err, &notLeader))
+	assert.Equal(players[0], g.Snapshot().Leader)
+
+	assert.Nil(g.TransferLeader(&player.FingerPoint{From: players[0], To: players[2]}))
+	assert.Equal(players[2], g.Snapshot().Leader)
+}
+
+func TestKick(t *testing.T) {
+	assert := assert.New(t)
+	g, players := lobby(3)
+	leader, kicked := players[0], players[1]
+	kicked.AccountID = uuid.New()
+
+	var notLeader *NotLeaderError
+	assert.True(errors.As(g.Kick(&player.FingerPoint{From: kicked, To: leader}), &notLeader))
+	var fpErr *FingerPointError
+	assert.True(errors.As(g.Kick(&player.FingerPoint{From: leader, To: leader}), &fpErr))
+
+	password := g.Password()
+	assert.Nil(g.Kick(&player.FingerPoint{From: leader, To: kicked}))
+	_, ok := g.Player(kicked.ID)
+	assert.False(ok)
+	assert.NotEqual(password, g.Password(), "the kicked player knows the old password")
+	assert.True(g.IsBanned(kicked.AccountID))
+	assert.False(g.IsBanned(uuid.Nil))
+
+	// with the same account, they can't come back even with the new one
+	again := player.NewPlayer(player.NewMockCommunicator())
+	again.AccountID = kicked.AccountID
+	var banned *BannedError
+	assert.True(errors.As(g.AddPlayer(again), &banned))
+	assert.Len(g.Snapshot().Players, 2)
+
+	// but someone else from the same address can
+	neighbour := player.NewPlayer(player.NewMockCommunicator())
+	neighbour.Addr = kicked.Addr
+	assert.Nil(g.AddPlayer(neighbour))
+	assert.Len(g.Snapshot().Players, 3)
+
+	// nobody can be kicked once the game is underway
+	g.AddPlayer(player.NewPlayer(player.NewMockCommunicator()))
+	g.AddPlayer(player.NewPlayer(player.NewMockCommunicator()))
+	assert.Nil(g.ChooseRoleset("Vanilla Fiver"))
+	assert.Nil(g.Start())
+	var stateErr *StateError
+	assert.True(errors.As(g.Kick(&player.FingerPoint{From: leader, To: players[2]}), &stateErr))
+}
+
+func TestAddPlayerOnlyInSetup(t *testing.T) {
+	assert := assert.New(t)
+	g, _, _ := vanillaFiver(t, ModKill)
+
+	late := player.NewPlayer(player.NewMockCommunicator())
+	var stateErr *StateError
+	assert.True(errors.As(g.AddPlayer(late), &stateErr))
+	_, ok := g.Player(late.ID)
+	assert.False(ok)
+}
+
+func TestSignedInPlayersKeepTheirName(t *testing.T) {
+	assert := assert.New(t)
+	g, players := lobby(2)
+	c := player.NewMockCommunicator()
+	signedIn := player.NewPlayer(c)
+	signedIn.AccountID = uuid.New()
+	signedIn.SetName("Alice")
+	assert.Nil(g.AddPlayer(signedIn))
+
+	g.mu.Lock()
+	var accountName *AccountNameError
+	assert.True(errors.As(g.setName(signedIn, "Mallory"), &accountName))
+	assert.Nil(g.setName(players[1], "Bob"))
+	g.mu.Unlock()
+	assert.Equal("Alice", signedIn.Name)
+	assert.Equal("Bob", players[1].Name)
+
+	g.gameChannel <- &gamechannel.Activity{Type: gamechannel.SetName, From: signedIn.ID, Value: "Mallory", RequestID: "name-1"}
+	assert.Eventually(func() bool { return len(errorMessages(c)) == 1 }, time.Second, time.Millisecond)
+	assert.Equal("accountName", errorMessages(c)[0].Code)
+	assert.Equal("name-1", errorMessages(c)[0].RequestID)
+}
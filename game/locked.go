@@ -0,0 +1,271 @@
+package game
+
+import (
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/role/roleset"
+
+	"github.com/google/uuid"
+)
+
+// The methods in this file are the game's entry points for other goroutines.
+// Each takes the game's lock and calls the unexported method that does the
+// work; code inside the package, which already holds the lock, calls those
+// directly.
+
+func (g *Game) SetLeader(p *player.Player) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.setLeader(p)
+}
+
+func (g *Game) State() GameState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state
+}
+
+// AddPlayer seats p in a game that hasn't started, returning an error if it
+// has or if p is banned from it.
+func (g *Game) AddPlayer(p *player.Player) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.addPlayer(p)
+}
+
+func (g *Game) ChooseRoleset(slug string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.chooseRoleset(slug)
+}
+
+func (g *Game) Start() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.start()
+}
+
+func (g *Game) Vote(fp *player.FingerPoint) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.vote(fp)
+}
+
+func (g *Game) SetNightAction(fp *player.FingerPoint) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.setNightAction(fp)
+}
+
+// KillPlayer kills p for the given cause. As with any death, a Revenge
+// Hunter gets their shot unless cause is server.ModKilled.
+func (g *Game) KillPlayer(p *player.Player, cause server.DeathCause) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.killPlayer(p, cause)
+}
+
+func (g *Game) EndGame(winner role.PlayerType) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.endGame(winner)
+}
+
+func (g *Game) Parity() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.parity()
+}
+
+// Reset puts a finished game back into setup on behalf of p, who must be the
+// leader.
+func (g *Game) Reset(p *player.Player) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.reset(p)
+}
+
+func (g *Game) ToGameOverMessage() *server.GameOverMessage {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.toGameOverMessage()
+}
+
+func (g *Game) AliveMaxEvils() []*player.Player {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.aliveMaxEvils()
+}
+
+func (g *Game) AlivePlayersByType() (maxes []*player.Player, nonmaxes []*player.Player) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.alivePlayersByType()
+}
+
+func (g *Game) IsDay() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.isDay()
+}
+
+func (g *Game) IsNight() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.isNight()
+}
+
+func (g *Game) Broadcast(t server.MessageType, payload interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.broadcast(t, payload)
+}
+
+// TransferLeader hands leadership from fp.From, who must be the leader, to
+// fp.To.
+func (g *Game) TransferLeader(fp *player.FingerPoint) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.transferLeader(fp)
+}
+
+// Shoot takes fp.From's revenge shot at fp.To. fp.From must be a hunter who
+// has just died.
+func (g *Game) Shoot(fp *player.FingerPoint) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.shoot(fp)
+}
+
+// SetLynchRule chooses the lynch rule on behalf of p, who must be the leader.
+func (g *Game) SetLynchRule(p *player.Player, rule LynchRule, dayLength time.Duration) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.setLynchRule(p, rule, dayLength)
+}
+
+// SetRevealMode chooses the reveal mode on behalf of p, who must be the
+// leader.
+func (g *Game) SetRevealMode(p *player.Player, mode RevealMode) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.setRevealMode(p, mode)
+}
+
+// ChooseLovers links a and b on behalf of p, who must be a Cupid, on night 0.
+func (g *Game) ChooseLovers(p, a, b *player.Player) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.chooseLovers(p, a, b)
+}
+
+// UsePotion sets p's potion for tonight on target. p must be a Witch who
+// still has that potion; target is ignored for NoPotion.
+func (g *Game) UsePotion(p *player.Player, potion Potion, target *player.Player) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.choosePotion(p, potion, target)
+}
+
+// Kick removes fp.To from the game on behalf of fp.From, who must be the
+// leader. The game's password changes, so they can't rejoin unless they are
+// given the new one, and if they signed in, their account can't rejoin at all.
+func (g *Game) Kick(fp *player.FingerPoint) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.kick(fp)
+}
+
+// IsBanned reports whether the account with accountID has been kicked.
+func (g *Game) IsBanned(accountID uuid.UUID) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return accountID != uuid.Nil && g.bannedAccts[accountID]
+}
+
+// Password is what players join the game with. It changes when a player is
+// kicked.
+func (g *Game) Password() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.password
+}
+
+// Substitute claims a seat left by a player who quit, if the game is holding
+// one. The newcomer should then take it over with the seat's Replace.
+func (g *Game) Substitute(addr string, accountID uuid.UUID) (*player.Player, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.substitute(addr, accountID)
+}
+
+// Close stops the game. It is safe to call more than once.
+func (g *Game) Close() {
+	g.cancel()
+}
+
+// Done is closed once the game has been told to stop.
+func (g *Game) Done() <-chan struct{} {
+	return g.ctx.Done()
+}
+
+// Player looks up a player in the game by ID.
+func (g *Game) Player(id uuid.UUID) (*player.Player, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.players[id]
+	return p, ok
+}
+
+// AccountID returns the account p is signed in with, which can change when a
+// substitute takes over their seat.
+func (g *Game) AccountID(p *player.Player) uuid.UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return p.AccountID
+}
+
+// A Snapshot is a copy of a game's state at one point in time. Its slices
+// belong to the caller, though the players in them are shared with the game.
+type Snapshot struct {
+	ID           uuid.UUID
+	State        GameState
+	Phase        int
+	Leader       *player.Player
+	Players      []*player.Player
+	AlivePlayers []*player.Player
+	Roleset      *roleset.Roleset
+	Winner       role.PlayerType
+}
+
+func (g *Game) Snapshot() *Snapshot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s := &Snapshot{
+		ID:           g.ID,
+		State:        g.state,
+		Phase:        g.phase,
+		Leader:       g.leader,
+		Players:      make([]*player.Player, 0, len(g.players)),
+		AlivePlayers: make([]*player.Player, 0, len(g.alivePlayers)),
+		Roleset:      g.roleset,
+		Winner:       g.winner,
+	}
+	for _, p := range g.players {
+		s.Players = append(s.Players, p)
+	}
+	for _, p := range g.alivePlayers {
+		s.AlivePlayers = append(s.AlivePlayers, p)
+	}
+	return s
+}
+
+// Timeline is everything that has happened in the game so far, oldest first.
+func (g *Game) Timeline() []*server.TimelineEvent {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]*server.TimelineEvent{}, g.timeline...)
+}
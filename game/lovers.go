@@ -0,0 +1,76 @@
+package game
+
+import (
+	"log/slog"
+	"math/rand"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+)
+
+// linkLovers makes a and b lovers: each is told who the other is, and if one
+// dies so does the other.
+func (g *Game) linkLovers(a, b *player.Player) {
+	slog.Info("linking lovers", "a", a, "b", b)
+	g.lovers = []*player.Player{a, b}
+	a.Role.SetLover()
+	b.Role.SetLover()
+	a.AddView(player.NewAttributeView(b, role.LoverAttribute, true, g.phase))
+	b.AddView(player.NewAttributeView(a, role.LoverAttribute, true, g.phase))
+	g.record(&server.TimelineEvent{Type: server.LoversLinked, From: a.ID, To: b.ID})
+}
+
+// partner is p's lover, or nil if they don't have one.
+func (g *Game) partner(p *player.Player) *player.Player {
+	switch {
+	case len(g.lovers) != 2:
+		return nil
+	case g.lovers[0] == p:
+		return g.lovers[1]
+	case g.lovers[1] == p:
+		return g.lovers[0]
+	}
+	return nil
+}
+
+// randomLovers links two players at random, for rolesets with lovers but no
+// Cupid to choose them.
+func (g *Game) randomLovers() {
+	perm := rand.Perm(len(g.playerSlice))
+	g.linkLovers(g.playerSlice[perm[0]], g.playerSlice[perm[1]])
+}
+
+// awaitingCupid reports whether night 0 is waiting for a Cupid to choose the
+// lovers.
+func (g *Game) awaitingCupid() bool {
+	if g.lovers != nil {
+		return false
+	}
+	for _, p := range g.alivePlayers {
+		if p.Role.ChoosesLovers() {
+			return true
+		}
+	}
+	return false
+}
+
+// chooseLovers links a and b on behalf of p, who must be a Cupid, which ends
+// night 0.
+func (g *Game) chooseLovers(p, a, b *player.Player) error {
+	if g.state != Running {
+		return &StateError{NeedState: Running, InState: g.state}
+	}
+	if g.phase != 0 || g.lovers != nil {
+		return &PhaseError{GamePhase: g.phase}
+	}
+	if !p.Role.ChoosesLovers() || !p.Role.Alive {
+		return &NotCupidError{Player: p}
+	}
+	if a == b {
+		return &FingerPointError{FingerPoint: &player.FingerPoint{From: a, To: b}}
+	}
+	g.linkLovers(a, b)
+	g.checkNightActions()
+	return nil
+}
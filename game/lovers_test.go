@@ -0,0 +1,110 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/win"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loverView is the view p was given of their lover, if any.
+func loverView(p *player.Player) *player.View {
+	for _, v := range p.Views {
+		if v.Attribute == role.LoverAttribute {
+			return v
+		}
+	}
+	return nil
+}
+
+func TestCupidChoosesLovers(t *testing.T) {
+	assert := assert.New(t)
+	g, players := lobby(8)
+	require.Nil(t, g.ChooseRoleset("Cupid's Eight"))
+	require.Nil(t, g.Start())
+
+	var cupid, wolf *player.Player
+	villagers := []*player.Player{}
+	for _, p := range players {
+		switch {
+		case p.Role.ChoosesLovers():
+			cupid = p
+		case p.Role.IsMaxEvil():
+			wolf = p
+		case p.Role.Name == "Villager":
+			villagers = append(villagers, p)
+		}
+	}
+	assert.True(g.IsNight(), "night 0 waits for Cupid")
+	assert.Equal(0, g.Snapshot().Phase)
+
+	var notCupid *NotCupidError
+	assert.True(errors.As(g.ChooseLovers(wolf, wolf, villagers[0]), &notCupid))
+	var fpErr *FingerPointError
+	assert.True(errors.As(g.ChooseLovers(cupid, wolf, wolf), &fpErr))
+
+	assert.Nil(g.ChooseLovers(cupid, wolf, villagers[0]))
+	assert.True(g.IsDay())
+	assert.Equal(wolf, loverView(villagers[0]).Player)
+	assert.Equal(villagers[0], loverView(wolf).Player)
+	assert.Nil(loverView(cupid))
+	var phaseErr *PhaseError
+	assert.True(errors.As(g.ChooseLovers(cupid, villagers[1], villagers[2]), &phaseErr))
+
+	// lynching one lover takes the other too
+	voteOut(t, g, villagers[0], cupid, villagers[1], villagers[2], villagers[3])
+	assert.False(wolf.Role.Alive)
+	g.mu.Lock()
+	last := g.timeline[len(g.timeline)-1]
+	g.mu.Unlock()
+	assert.Equal(server.TimelineEventType(server.Death), last.Type)
+	assert.Equal(wolf.ID, last.To)
+	assert.Equal(server.DeathCause(server.Heartbreak), last.Cause)
+}
+
+func TestCrossTeamLoversWinTogether(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	lover := villagers[0]
+	g.mu.Lock()
+	g.linkLovers(wolf, lover)
+	g.roleset.Win = win.Lovers{Then: win.Parity{}}
+	g.mu.Unlock()
+
+	voteOut(t, g, villagers[1], lover, villagers[2], villagers[3])
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[2]}))
+
+	// on parity alone the wolf would win here, but the lovers are the last two
+	voteOut(t, g, villagers[3], wolf, lover)
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Neutral), g.Snapshot().Winner)
+	assert.ElementsMatch([]uuid.UUID{wolf.ID, lover.ID}, g.ToGameOverMessage().Winners)
+	for _, r := range g.ToGameOverMessage().Roles {
+		assert.Equal(r.ID == wolf.ID || r.ID == lover.ID, r.Lover, r.Name)
+	}
+}
+
+func TestRandomLovers(t *testing.T) {
+	assert := assert.New(t)
+	g, players := lobby(7)
+	require.Nil(t, g.ChooseRoleset("Star-Crossed Seven"))
+	require.Nil(t, g.Start())
+	assert.True(g.IsDay(), "with no Cupid, night 0 doesn't wait")
+
+	lovers := []*player.Player{}
+	for _, p := range players {
+		if p.Role.IsLover() {
+			lovers = append(lovers, p)
+		}
+	}
+	require.Len(t, lovers, 2)
+	assert.Equal(lovers[1], loverView(lovers[0]).Player)
+	assert.Equal(lovers[0], loverView(lovers[1]).Player)
+}
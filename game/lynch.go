@@ -0,0 +1,142 @@
+package game
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+)
+
+// A LynchRule says how many votes it takes to lynch a player and end the day.
+type LynchRule string
+
+const (
+	// Half lynches as soon as a player has the votes of half of the living,
+	// rounded up. With an even number alive, that is first to half.
+	Half LynchRule = "half"
+	// StrictMajority lynches once a player has more than half of the votes.
+	StrictMajority = "strictMajority"
+	// Supermajority lynches once a player has two thirds of the votes,
+	// rounded up.
+	Supermajority = "supermajority"
+	// PluralityAtDeadline never ends the day early: once the game's DayLength
+	// is up, whoever leads the tally is lynched, ties broken by LHLV.
+	PluralityAtDeadline = "pluralityAtDeadline"
+)
+
+// DefaultDayLength is how long a day lasts under PluralityAtDeadline, unless
+// the leader chooses otherwise.
+const DefaultDayLength = 5 * time.Minute
+
+// VotesNeeded is how many votes it takes to lynch a player with alive
+// players voting, or 0 if the rule has no threshold.
+func (r LynchRule) VotesNeeded(alive int) int {
+	switch r {
+	case Half:
+		return (alive + 1) / 2
+	case StrictMajority:
+		return alive/2 + 1
+	case Supermajority:
+		return (2*alive + 2) / 3
+	}
+	return 0
+}
+
+func (r LynchRule) valid() bool {
+	switch r {
+	case Half, StrictMajority, Supermajority, PluralityAtDeadline:
+		return true
+	}
+	return false
+}
+
+// setLynchRule lets the leader choose the lynch rule before the game starts.
+// dayLength only matters for PluralityAtDeadline; zero means the default.
+func (g *Game) setLynchRule(p *player.Player, rule LynchRule, dayLength time.Duration) error {
+	if p != g.leader {
+		return &NotLeaderError{Player: p}
+	}
+	if g.state != Setup {
+		return &StateError{NeedState: Setup, InState: g.state}
+	}
+	if !rule.valid() {
+		return &LynchRuleError{Rule: rule}
+	}
+	if dayLength <= 0 {
+		dayLength = DefaultDayLength
+	}
+	slog.Info("lynch rule chosen", "rule", rule, "dayLength", dayLength)
+	g.LynchRule = rule
+	g.DayLength = dayLength
+	g.broadcast(server.LynchRuleSet, g.lynchRuleMessage())
+	return nil
+}
+
+func (g *Game) lynchRuleMessage() *server.LynchRule {
+	m := &server.LynchRule{Rule: string(g.LynchRule)}
+	if g.LynchRule == PluralityAtDeadline {
+		m.DayLength = int(g.DayLength / time.Second)
+	}
+	return m
+}
+
+// broadcastTally sends everyone the tally, with how many votes a lynch takes
+// right now.
+func (g *Game) broadcastTally() {
+	g.updateTally()
+	g.broadcast(server.TallyChanged, g.tally)
+}
+
+func (g *Game) updateTally() {
+	g.tally.VotesNeeded = g.LynchRule.VotesNeeded(len(g.alivePlayers))
+	if !g.deadline.IsZero() {
+		deadline := g.deadline
+		g.tally.Deadline = &deadline
+	}
+}
+
+// startDayTimer ends the day after DayLength, if the lynch rule has a
+// deadline.
+func (g *Game) startDayTimer() {
+	g.stopDayTimer()
+	if g.LynchRule != PluralityAtDeadline {
+		return
+	}
+	phase := g.phase
+	g.deadline = time.Now().Add(g.DayLength)
+	g.dayTimer = time.AfterFunc(g.DayLength, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.endDayAtDeadline(phase)
+	})
+}
+
+func (g *Game) stopDayTimer() {
+	if g.dayTimer != nil {
+		g.dayTimer.Stop()
+		g.dayTimer = nil
+	}
+	g.deadline = time.Time{}
+}
+
+// endDayAtDeadline lynches the leader of the tally, if anyone has a vote, and
+// moves on to night. It does nothing if the day it was set for is over. If a
+// revenge shot is being taken, the day ends once it has been.
+func (g *Game) endDayAtDeadline(phase int) {
+	if g.ctx.Err() != nil || g.state != Running || g.phase != phase {
+		return
+	}
+	if hunter := g.shooter(); hunter != nil {
+		// the shot may change who leads, or end the game; afterShots ends the
+		// day once it's taken, as the deadline has passed
+		slog.Info("day deadline reached during a revenge shot", "phase", phase, "hunter", hunter)
+		return
+	}
+	slog.Info("day deadline reached", "phase", phase)
+	g.endingPhase = true
+	if leader := g.tally.List[0]; len(leader.Votes) > 0 {
+		g.killPlayer(leader.Player, server.Lynched)
+	}
+	g.moveOn()
+}
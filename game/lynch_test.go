@@ -0,0 +1,145 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lynchFiver starts a Vanilla Fiver under rule, returning the wolf and the
+// villagers.
+func lynchFiver(t *testing.T, rule LynchRule, dayLength time.Duration) (*Game, *player.Player, []*player.Player) {
+	players := []*player.Player{}
+	for i := 0; i < 5; i++ {
+		players = append(players, player.NewPlayer(player.NewMockCommunicator()))
+	}
+	g := NewGame(players[0])
+	for _, p := range players[1:] {
+		g.AddPlayer(p)
+	}
+	require.Nil(t, g.SetLynchRule(players[0], rule, dayLength))
+	require.Nil(t, g.ChooseRoleset("Vanilla Fiver"))
+	require.Nil(t, g.Start())
+
+	var wolf *player.Player
+	villagers := []*player.Player{}
+	for _, p := range players {
+		if p.Role.IsMaxEvil() {
+			wolf = p
+		} else {
+			villagers = append(villagers, p)
+		}
+	}
+	return g, wolf, villagers
+}
+
+func TestVotesNeeded(t *testing.T) {
+	for _, tc := range []struct {
+		rule  LynchRule
+		alive int
+		want  int
+	}{
+		{Half, 5, 3},
+		{Half, 4, 2},
+		{StrictMajority, 5, 3},
+		{StrictMajority, 4, 3},
+		{Supermajority, 5, 4},
+		{Supermajority, 6, 4},
+		{Supermajority, 3, 2},
+		{PluralityAtDeadline, 5, 0},
+	} {
+		assert.Equal(t, tc.want, tc.rule.VotesNeeded(tc.alive), "%s with %d alive", tc.rule, tc.alive)
+	}
+}
+
+func TestSetLynchRule(t *testing.T) {
+	assert := assert.New(t)
+	leader := player.NewPlayer(player.NewMockCommunicator())
+	other := player.NewPlayer(player.NewMockCommunicator())
+	g := NewGame(leader)
+	g.AddPlayer(other)
+	assert.Equal(LynchRule(Half), g.LynchRule)
+
+	var notLeader *NotLeaderError
+	assert.True(errors.As(g.SetLynchRule(other, StrictMajority, 0), &notLeader))
+
+	err := g.SetLynchRule(leader, "coinFlip", 0)
+	var unknown *LynchRuleError
+	assert.True(errors.As(err, &unknown))
+	assert.Equal("unknownLynchRule", server.NewErrorMessage("", err).Code)
+
+	assert.Nil(g.SetLynchRule(leader, PluralityAtDeadline, 0))
+	assert.Equal(DefaultDayLength, g.DayLength)
+	assert.Equal(&server.LynchRule{Rule: PluralityAtDeadline, DayLength: 300}, g.lynchRuleMessage())
+}
+
+func TestSetLynchRuleOnlyInSetup(t *testing.T) {
+	g, _, _ := lynchFiver(t, StrictMajority, 0)
+	var stateErr *StateError
+	assert.True(t, errors.As(g.SetLynchRule(g.Snapshot().Leader, Half, 0), &stateErr))
+	g.mu.Lock()
+	assert.Equal(t, 3, g.tally.VotesNeeded)
+	g.mu.Unlock()
+}
+
+func TestSupermajority(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := lynchFiver(t, Supermajority, 0)
+	g.mu.Lock()
+	assert.Equal(4, g.tally.VotesNeeded)
+	g.mu.Unlock()
+
+	// three of five is enough for half, but not here
+	for _, p := range villagers[:3] {
+		assert.Nil(g.Vote(&player.FingerPoint{From: p, To: wolf}))
+	}
+	assert.True(g.IsDay())
+	assert.Nil(g.Vote(&player.FingerPoint{From: villagers[3], To: wolf}))
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+}
+
+func TestPluralityAtDeadline(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := lynchFiver(t, PluralityAtDeadline, time.Hour)
+	g.mu.Lock()
+	assert.Equal(0, g.tally.VotesNeeded)
+	assert.NotNil(g.tally.Deadline)
+	g.mu.Unlock()
+
+	// everyone voting for the wolf doesn't end the day
+	for _, p := range villagers {
+		assert.Nil(g.Vote(&player.FingerPoint{From: p, To: wolf}))
+	}
+	assert.True(g.IsDay())
+
+	// the wolf still leads at the deadline, three votes to one
+	assert.Nil(g.Vote(&player.FingerPoint{From: villagers[0], To: villagers[1]}))
+	g.mu.Lock()
+	g.endDayAtDeadline(g.phase - 1)
+	assert.True(g.isDay(), "an old day's timer does nothing")
+	g.endDayAtDeadline(g.phase)
+	g.mu.Unlock()
+	assert.Equal(Finished, g.State())
+	assert.False(wolf.Role.Alive)
+}
+
+func TestDayTimer(t *testing.T) {
+	g, _, villagers := lynchFiver(t, PluralityAtDeadline, 10*time.Millisecond)
+
+	// nobody votes, so nobody dies, but the day still ends
+	assert.Eventually(t, g.IsNight, time.Second, time.Millisecond)
+	for _, p := range villagers {
+		assert.True(t, p.Role.Alive)
+	}
+	g.mu.Lock()
+	assert.True(t, g.deadline.IsZero())
+	g.mu.Unlock()
+}
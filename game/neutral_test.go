@@ -0,0 +1,83 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/win"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// voteOut has each of voters vote for target.
+func voteOut(t *testing.T, g *Game, target *player.Player, voters ...*player.Player) {
+	for _, p := range voters {
+		require.Nil(t, g.Vote(&player.FingerPoint{From: p, To: target}))
+	}
+	require.False(t, target.Role.Alive)
+}
+
+func TestJesterWinsIfLynched(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	jester := villagers[0]
+	g.mu.Lock()
+	jester.Role = role.Jester()
+	g.mu.Unlock()
+
+	voteOut(t, g, jester, villagers[1:]...)
+	assert.Equal(Running, g.State(), "the game goes on without them")
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[1]}))
+	voteOut(t, g, wolf, villagers[2:]...)
+
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+	assert.ElementsMatch([]uuid.UUID{jester.ID, villagers[1].ID, villagers[2].ID, villagers[3].ID}, g.ToGameOverMessage().Winners)
+	for _, pr := range g.result().Players {
+		assert.Equal(pr.PlayerID != wolf.ID, pr.Won, pr.Name)
+	}
+}
+
+func TestSerialKillerWinsAlone(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	killer := villagers[0]
+	g.mu.Lock()
+	killer.Role = role.SerialKiller()
+	g.roleset.Win = win.Loners{Then: win.Parity{}}
+	g.mu.Unlock()
+
+	voteOut(t, g, villagers[1], killer, villagers[2], villagers[3])
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[2]}))
+	assert.True(g.IsNight(), "the killer hasn't chosen yet")
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: killer, To: villagers[3]}))
+
+	assert.False(villagers[2].Role.Alive)
+	assert.False(villagers[3].Role.Alive)
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Neutral), g.Snapshot().Winner)
+	assert.Equal([]uuid.UUID{killer.ID}, g.ToGameOverMessage().Winners)
+}
+
+func TestSerialKillerKeepsGameGoing(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	killer := villagers[0]
+	g.mu.Lock()
+	killer.Role = role.SerialKiller()
+	g.roleset.Win = win.Loners{Then: win.Parity{}}
+	g.mu.Unlock()
+
+	// the wolf is gone, but the village hasn't won yet
+	voteOut(t, g, wolf, villagers[:3]...)
+	assert.Equal(Running, g.State())
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: killer, To: villagers[1]}))
+	voteOut(t, g, killer, villagers[2:]...)
+
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+	assert.ElementsMatch([]uuid.UUID{villagers[1].ID, villagers[2].ID, villagers[3].ID}, g.ToGameOverMessage().Winners)
+}
@@ -8,6 +8,15 @@ import (
 
 var passwordGenerator *spg.WLRecipe
 
+func newPassword() string {
+	pw, err := passwordGenerator.Generate()
+	if err != nil {
+		slog.Error("error generating password", "error", err)
+		return ""
+	}
+	return pw.String()
+}
+
 func init() {
 	wordList, err := spg.NewWordList([]string{
 		"furry",
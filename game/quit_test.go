@@ -0,0 +1,194 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vanillaFiver starts a Vanilla Fiver, returning the wolf and the villagers.
+func vanillaFiver(t *testing.T, policy QuitPolicy) (*Game, *player.Player, []*player.Player) {
+	g, wolf, villagers, _ := vanillaFiverWithComms(t, policy)
+	return g, wolf, villagers
+}
+
+func vanillaFiverWithComms(t *testing.T, policy QuitPolicy) (*Game, *player.Player, []*player.Player, map[*player.Player]*player.MockCommunicator) {
+	players := []*player.Player{}
+	comms := make(map[*player.Player]*player.MockCommunicator)
+	for i := 0; i < 5; i++ {
+		c := player.NewMockCommunicator()
+		p := player.NewPlayer(c)
+		players = append(players, p)
+		comms[p] = c
+	}
+	g := NewGame(players[0])
+	g.QuitPolicy = policy
+	for _, p := range players[1:] {
+		g.AddPlayer(p)
+	}
+	require.Nil(t, g.ChooseRoleset("Vanilla Fiver"))
+	require.Nil(t, g.Start())
+
+	var wolf *player.Player
+	villagers := []*player.Player{}
+	for _, p := range players {
+		if p.Role.IsMaxEvil() {
+			wolf = p
+		} else {
+			villagers = append(villagers, p)
+		}
+	}
+	return g, wolf, villagers, comms
+}
+
+func quit(g *Game, p *player.Player) {
+	g.handle(&gamechannel.Activity{Type: gamechannel.Quit, From: p.ID})
+}
+
+// lynch votes a villager out on day 1 so that the game moves to night.
+func lynch(t *testing.T, g *Game, villagers []*player.Player) {
+	for _, p := range villagers[1:] {
+		require.Nil(t, g.Vote(&player.FingerPoint{From: p, To: villagers[0]}))
+	}
+	require.True(t, g.IsNight())
+}
+
+func TestModKillDuringDay(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	quitter := villagers[0]
+
+	assert.Nil(g.Vote(&player.FingerPoint{From: quitter, To: wolf}))
+	assert.Nil(g.Vote(&player.FingerPoint{From: wolf, To: quitter}))
+	quit(g, quitter)
+
+	assert.False(quitter.Role.Alive)
+	_, ok := g.Player(quitter.ID)
+	assert.False(ok)
+	assert.NotContains(g.Snapshot().AlivePlayers, quitter)
+	assert.Equal(Running, g.State())
+
+	// neither their vote nor the one for them counts any more
+	g.mu.Lock()
+	assert.Len(g.tally.List, 4)
+	for _, item := range g.tally.List {
+		assert.NotEqual(quitter, item.Player)
+		assert.Empty(item.Votes)
+	}
+	assert.Nil(g.tally.Inverted[wolf])
+	g.mu.Unlock()
+
+	// they are still revealed when the game ends
+	g.EndGame(role.Good)
+	revealed := false
+	for _, r := range g.ToGameOverMessage().Roles {
+		revealed = revealed || r.ID == quitter.ID
+	}
+	assert.True(revealed)
+}
+
+func TestModKillEndsGame(t *testing.T) {
+	g, wolf, _ := vanillaFiver(t, ModKill)
+
+	quit(g, wolf)
+
+	assert.Equal(t, Finished, g.State())
+	assert.Equal(t, role.PlayerType(role.Good), g.Snapshot().Winner)
+}
+
+func TestModKillCanEndDay(t *testing.T) {
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+
+	// two of the four remaining players will be enough once one leaves
+	assert.Nil(t, g.Vote(&player.FingerPoint{From: villagers[1], To: wolf}))
+	assert.Nil(t, g.Vote(&player.FingerPoint{From: villagers[2], To: wolf}))
+	assert.True(t, g.IsDay())
+	quit(g, villagers[0])
+
+	assert.Equal(t, Finished, g.State())
+	assert.False(t, wolf.Role.Alive)
+}
+
+func TestModKillOfNightKillTarget(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers, comms := vanillaFiverWithComms(t, ModKill)
+	lynch(t, g, villagers)
+	phase := g.Snapshot().Phase
+
+	// the wolf is the only one with a night action, so its choice would end
+	// the night straight away; record it as if something else were pending
+	target := villagers[1]
+	g.mu.Lock()
+	g.nightActions[wolf] = &player.FingerPoint{From: wolf, To: target}
+	g.nightKill = g.nightActions[wolf]
+	g.mu.Unlock()
+	quit(g, target)
+
+	// the wolf has to choose again
+	assert.Equal(Running, g.State())
+	assert.Equal(phase, g.Snapshot().Phase)
+	assert.Nil(g.nightKill)
+	assert.Eventually(func() bool { return len(errorMessages(comms[wolf])) == 1 }, time.Second, time.Millisecond)
+	assert.Equal("playerDead", errorMessages(comms[wolf])[0].Code)
+
+	// which leaves the wolf with parity
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[2]}))
+	assert.False(villagers[2].Role.Alive)
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Evil), g.Snapshot().Winner)
+}
+
+func TestHoldForSubstitute(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, HoldForSubstitute)
+	lynch(t, g, villagers)
+
+	// the wolf leaves at night; the game waits for someone to take over
+	quit(g, wolf)
+	assert.Equal(Running, g.State())
+	assert.True(wolf.Role.Alive)
+	assert.True(g.IsNight())
+	_, ok := g.Player(wolf.ID)
+	assert.True(ok)
+
+	seat, ok := g.Substitute("192.0.2.1", uuid.Nil)
+	assert.True(ok)
+	assert.Equal(wolf, seat)
+	_, ok = g.Substitute("192.0.2.2", uuid.Nil)
+	assert.False(ok, "the seat has been claimed")
+
+	c := player.NewMockCommunicator()
+	seat.Replace(c)
+	assert.Eventually(func() bool {
+		for _, raw := range c.Written() {
+			if string(raw) == `{"messageType":"idSet","payload":"`+wolf.ID.String()+`"}` {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+
+	// the substitute plays on as the wolf
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: seat, To: villagers[1]}))
+	assert.True(g.IsDay())
+	assert.False(villagers[1].Role.Alive)
+}
+
+func TestHoldWithdrawsVote(t *testing.T) {
+	g, wolf, villagers := vanillaFiver(t, HoldForSubstitute)
+
+	assert.Nil(t, g.Vote(&player.FingerPoint{From: villagers[0], To: wolf}))
+	quit(g, villagers[0])
+
+	g.mu.Lock()
+	assert.Nil(t, g.tally.Inverted[villagers[0]])
+	assert.Len(t, g.tally.List, 5, "they can still be voted for")
+	g.mu.Unlock()
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReset(t *testing.T) {
+	assert := assert.New(t)
+	leader := player.NewPlayer(player.NewMockCommunicator())
+	g := NewGame(leader)
+	other := player.NewPlayer(player.NewMockCommunicator())
+	g.AddPlayer(other)
+	for i := 0; i < 3; i++ {
+		g.AddPlayer(player.NewPlayer(player.NewMockCommunicator()))
+	}
+	assert.Nil(g.ChooseRoleset("Vanilla Fiver"))
+	password := g.password
+
+	var stateErr *StateError
+	assert.True(errors.As(g.Reset(leader), &stateErr))
+
+	assert.Nil(g.Start())
+	assert.True(errors.As(g.Reset(leader), &stateErr))
+	assert.Equal(Running, stateErr.InState)
+
+	// kill a player so that the roleset has a dead role in it
+	for _, p := range g.Snapshot().Players {
+		if !p.Role.IsMaxEvil() {
+			g.KillPlayer(p, server.ModKilled)
+			break
+		}
+	}
+	g.EndGame(role.Good)
+
+	var notLeader *NotLeaderError
+	assert.True(errors.As(g.Reset(other), &notLeader))
+	assert.Equal(Finished, g.State())
+
+	assert.Nil(g.Reset(leader))
+	s := g.Snapshot()
+	assert.Equal(Setup, s.State)
+	assert.Equal(0, s.Phase)
+	assert.Equal(leader, s.Leader)
+	assert.Equal(password, g.password)
+	assert.Len(s.Players, 5)
+	assert.Empty(s.AlivePlayers)
+	g.mu.Lock()
+	assert.Nil(g.tally)
+	g.mu.Unlock()
+	assert.Equal("Vanilla Fiver", s.Roleset.Name)
+	for _, r := range s.Roleset.Roles {
+		assert.True(r.Alive)
+	}
+	for _, p := range s.Players {
+		assert.Nil(p.Role)
+		assert.Empty(p.Views)
+	}
+
+	// and they can play again
+	assert.Nil(g.Start())
+	assert.Equal(Running, g.State())
+	assert.Len(g.Snapshot().AlivePlayers, 5)
+}
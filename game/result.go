@@ -0,0 +1,90 @@
+package game
+
+import (
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
+)
+
+// A Result is the outcome of one finished game, copied out of it so that it
+// can be kept after the game is reset or stopped. A game that is reset and
+// played again has a Result, with its own ID, for each time.
+type Result struct {
+	ID       uuid.UUID               `json:"id"`
+	GameID   uuid.UUID               `json:"gameId"`
+	Roleset  string                  `json:"roleset"`
+	Winner   role.PlayerType         `json:"winner"`
+	Finished time.Time               `json:"finished"`
+	Players  []*PlayerResult         `json:"players"`
+	Timeline []*server.TimelineEvent `json:"timeline"`
+}
+
+// A PlayerResult is how one player's game went. AccountID is uuid.Nil for
+// players who weren't signed in. Checks counts the views they made at night,
+// and Hits how many of those found what they were looking for.
+type PlayerResult struct {
+	PlayerID  uuid.UUID       `json:"playerId"`
+	AccountID uuid.UUID       `json:"accountId"`
+	Name      string          `json:"name"`
+	Role      string          `json:"role"`
+	Team      role.PlayerType `json:"team"`
+	MaxEvil   bool            `json:"maxEvil"`
+	Tinker    bool            `json:"tinker"`
+	Won       bool            `json:"won"`
+	Alive     bool            `json:"alive"`
+	Departed  bool            `json:"departed"`
+	Checks    int             `json:"checks"`
+	Hits      int             `json:"hits"`
+}
+
+func (g *Game) result() *Result {
+	r := &Result{
+		ID:       uuid.New(),
+		GameID:   g.ID,
+		Winner:   g.winner,
+		Finished: time.Now(),
+		Players:  []*PlayerResult{},
+		Timeline: append([]*server.TimelineEvent{}, g.timeline...),
+	}
+	if g.roleset != nil {
+		r.Roleset = g.roleset.Name
+	}
+	// every seat dealt a role, including those left empty by quitting
+	for _, p := range g.playerSlice {
+		_, seated := g.players[p.ID]
+		r.Players = append(r.Players, g.playerResult(p, !seated || g.vacant[p.ID]))
+	}
+	return r
+}
+
+func (g *Game) playerResult(p *player.Player, departed bool) *PlayerResult {
+	pr := &PlayerResult{
+		PlayerID:  p.ID,
+		AccountID: p.AccountID,
+		Name:      p.Name,
+		Departed:  departed,
+	}
+	if p.Role != nil {
+		pr.Role = p.Role.Name
+		pr.Team = p.Role.Team
+		pr.MaxEvil = p.Role.IsMaxEvil()
+		pr.Tinker = p.Role.IsTinker()
+		pr.Won = g.won(p)
+		pr.Alive = p.Role.Alive
+	}
+	// only the views they chose count, so not reveals, lovers, knowing the
+	// other wolves or N0 clears
+	for _, e := range g.timeline {
+		if e.Type == server.ViewResult && e.From == p.ID && e.Phase > 0 {
+			pr.Checks++
+			if e.Hit {
+				pr.Hits++
+			}
+		}
+	}
+	return pr
+}
@@ -0,0 +1,99 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnGameOver(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	wolf.AccountID = uuid.New()
+	results := make(chan *Result, 1)
+	g.OnGameOver = func(r *Result) { results <- r }
+
+	quit(g, villagers[0])
+	g.EndGame(role.Evil)
+
+	var r *Result
+	select {
+	case r = <-results:
+	case <-time.After(time.Second):
+		require.FailNow(t, "no result")
+	}
+	assert.Equal(g.ID, r.GameID)
+	assert.Equal("Vanilla Fiver", r.Roleset)
+	assert.Equal(role.PlayerType(role.Evil), r.Winner)
+	require.Len(t, r.Players, 5)
+	for _, p := range r.Players {
+		switch p.PlayerID {
+		case wolf.ID:
+			assert.Equal(wolf.AccountID, p.AccountID)
+			assert.Equal(role.PlayerType(role.Evil), p.Team)
+			assert.True(p.MaxEvil)
+			assert.True(p.Won)
+			assert.True(p.Alive)
+		case villagers[0].ID:
+			assert.True(p.Departed)
+			assert.False(p.Alive)
+		default:
+			assert.Equal(uuid.Nil, p.AccountID)
+			assert.False(p.Won)
+		}
+	}
+}
+
+func TestOnlyChosenViewsAreChecks(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	seer := villagers[1]
+	g.mu.Lock()
+	g.RevealMode = RevealTeam
+	seer.Role = role.Seer()
+	g.mu.Unlock()
+
+	// everyone is shown villagers[0]'s team, but only the seer checks anyone
+	lynch(t, g, villagers)
+	require.Nil(t, g.SetNightAction(&player.FingerPoint{From: seer, To: wolf}))
+	require.Nil(t, g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[2]}))
+	g.EndGame(role.Evil)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, pr := range g.result().Players {
+		if pr.PlayerID == seer.ID {
+			assert.Equal(1, pr.Checks)
+			assert.Equal(1, pr.Hits)
+		} else {
+			assert.Zero(pr.Checks, pr.Name)
+			assert.Zero(pr.Hits, pr.Name)
+		}
+	}
+}
+
+func TestEmptySeatIsInResult(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, HoldForSubstitute)
+
+	// nobody takes over before the game ends
+	quit(g, villagers[0])
+	g.EndGame(role.Evil)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	r := g.result()
+	require.Len(t, r.Players, 5)
+	for _, pr := range r.Players {
+		assert.Equal(pr.PlayerID == villagers[0].ID, pr.Departed, pr.Name)
+		if pr.PlayerID == wolf.ID {
+			assert.True(pr.Won)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"log/slog"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+)
+
+// A RevealMode says what everyone learns about a player when they die. The
+// game over message always shows the truth.
+type RevealMode string
+
+const (
+	// RevealRole shows the dead player's role.
+	RevealRole RevealMode = "role"
+	// RevealTeam only shows which team they were on.
+	RevealTeam = "team"
+	// VillagerFlip shows the role, except that aux evils and Tinkers flip as
+	// plain Villagers.
+	VillagerFlip = "villagerFlip"
+	// RevealNothing shows nothing; the dead simply leave the player list.
+	RevealNothing = "none"
+)
+
+func (m RevealMode) valid() bool {
+	switch m {
+	case RevealRole, RevealTeam, VillagerFlip, RevealNothing:
+		return true
+	}
+	return false
+}
+
+// setRevealMode lets the leader choose the reveal mode before the game
+// starts.
+func (g *Game) setRevealMode(p *player.Player, mode RevealMode) error {
+	if p != g.leader {
+		return &NotLeaderError{Player: p}
+	}
+	if g.state != Setup {
+		return &StateError{NeedState: Setup, InState: g.state}
+	}
+	if !mode.valid() {
+		return &RevealModeError{Mode: mode}
+	}
+	slog.Info("reveal mode chosen", "mode", mode)
+	g.RevealMode = mode
+	g.broadcast(server.RevealModeSet, g.RevealMode)
+	return nil
+}
+
+// revealPlayer tells everyone about a player who has died, as much as the
+// reveal mode allows.
+func (g *Game) revealPlayer(p *player.Player) {
+	slog.Info("revealing player", "player", p, "role", p.Role, "mode", g.RevealMode)
+	switch g.RevealMode {
+	case RevealTeam:
+		g.broadcastView(player.NewTeamView(p, p.Role.Team, g.phase))
+	case VillagerFlip:
+		g.broadcastView(player.NewRoleView(p, flip(p.Role), g.phase))
+	case RevealNothing:
+	default:
+		g.broadcastView(player.NewRoleView(p, p.Role, g.phase))
+	}
+}
+
+// flip is the role r is revealed as under VillagerFlip: aux evils and good
+// Tinkers show as Villagers. A Tinker dealt on the evil team, such as a
+// tinkered wolf, shows as what they are.
+func flip(r *role.Role) *role.Role {
+	if !r.IsAuxEvil() && !(r.IsTinker() && r.Team == role.Good) {
+		return r
+	}
+	v := role.Villager()
+	v.Alive = r.Alive
+	return v
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// deathView is what observer was shown about p dying, if anything.
+func deathView(observer, p *player.Player) *player.View {
+	for _, v := range observer.Views {
+		if v.Player == p && (v.Role != nil || v.Team != nil) {
+			return v
+		}
+	}
+	return nil
+}
+
+func TestRevealModes(t *testing.T) {
+	for _, tc := range []struct {
+		mode  RevealMode
+		check func(*assert.Assertions, *player.View)
+	}{
+		{RevealRole, func(assert *assert.Assertions, v *player.View) {
+			assert.Equal("Cultist", v.Role.Name)
+		}},
+		{RevealTeam, func(assert *assert.Assertions, v *player.View) {
+			assert.Nil(v.Role)
+			assert.Equal(role.PlayerType(role.Evil), *v.Team)
+		}},
+		{VillagerFlip, func(assert *assert.Assertions, v *player.View) {
+			assert.Equal("Villager", v.Role.Name)
+			assert.Equal(role.Good, v.Role.Team)
+			assert.False(v.Role.Alive)
+		}},
+		{RevealNothing, func(assert *assert.Assertions, v *player.View) {
+			assert.Nil(v)
+		}},
+	} {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			assert := assert.New(t)
+			g, wolf, villagers := vanillaFiver(t, ModKill)
+			g.mu.Lock()
+			g.RevealMode = tc.mode
+			villagers[0].Role = role.Cultist()
+			g.mu.Unlock()
+
+			lynch(t, g, villagers)
+			tc.check(assert, deathView(wolf, villagers[0]))
+			tc.check(assert, deathView(villagers[1], villagers[0]))
+
+			// the truth comes out at the end
+			g.EndGame(role.Good)
+			for _, r := range g.ToGameOverMessage().Roles {
+				if r.ID == villagers[0].ID {
+					assert.Equal("Cultist", r.Role.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestVillagerFlipKeepsWolves(t *testing.T) {
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	g.mu.Lock()
+	g.RevealMode = VillagerFlip
+	g.mu.Unlock()
+
+	for _, p := range villagers[:3] {
+		assert.Nil(t, g.Vote(&player.FingerPoint{From: p, To: wolf}))
+	}
+	assert.Equal(t, "Werewolf", deathView(villagers[0], wolf).Role.Name)
+}
+
+func TestEvilTinkerIsRevealed(t *testing.T) {
+	for _, mode := range []RevealMode{RevealRole, RevealTeam, VillagerFlip} {
+		t.Run(string(mode), func(t *testing.T) {
+			assert := assert.New(t)
+			g, wolf, villagers := vanillaFiver(t, ModKill)
+			g.mu.Lock()
+			g.RevealMode = mode
+			wolf.Role.SetTinker()
+			g.mu.Unlock()
+
+			for _, p := range villagers[:3] {
+				assert.Nil(g.Vote(&player.FingerPoint{From: p, To: wolf}))
+			}
+			v := deathView(villagers[0], wolf)
+			if mode == RevealTeam {
+				assert.Equal(role.PlayerType(role.Evil), *v.Team)
+			} else {
+				assert.Equal("Werewolf", v.Role.Name)
+			}
+		})
+	}
+}
+
+func TestSetRevealMode(t *testing.T) {
+	assert := assert.New(t)
+	leader := player.NewPlayer(player.NewMockCommunicator())
+	other := player.NewPlayer(player.NewMockCommunicator())
+	g := NewGame(leader)
+	g.AddPlayer(other)
+	assert.Equal(RevealMode(RevealRole), g.RevealMode)
+
+	var notLeader *NotLeaderError
+	assert.True(errors.As(g.SetRevealMode(other, RevealTeam), &notLeader))
+	err := g.SetRevealMode(leader, "tarot")
+	assert.Equal("unknownRevealMode", server.NewErrorMessage("", err).Code)
+
+	assert.Nil(g.SetRevealMode(leader, RevealNothing))
+	assert.Equal(RevealMode(RevealNothing), g.RevealMode)
+}
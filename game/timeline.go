@@ -0,0 +1,52 @@
+package game
+
+import (
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/tally"
+)
+
+// record adds an event to the game's timeline, in the current phase. Events
+// without a time happen now.
+func (g *Game) record(e *server.TimelineEvent) {
+	e.Phase = g.phase
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	g.timeline = append(g.timeline, e)
+}
+
+// recordUnvote records p's vote being withdrawn, if they have one today.
+func (g *Game) recordUnvote(p *player.Player) {
+	if g.tally == nil || !g.isDay() || g.tally.Inverted[p] == nil {
+		return
+	}
+	g.record(&server.TimelineEvent{Type: server.VoteWithdrawn, From: p.ID})
+}
+
+func (g *Game) recordView(from *player.Player, v *player.View) {
+	g.record(&server.TimelineEvent{
+		Type:      server.ViewResult,
+		From:      from.ID,
+		To:        v.Player.ID,
+		Attribute: v.Attribute,
+		Hit:       v.Hit,
+		// a Tinker turns the answer around
+		False: v.Hit != (v.Player.Role.Attributes&v.Attribute > 0),
+	})
+}
+
+// archiveDay keeps the log of the day that is ending, and sends everyone the
+// logs of every day so far. It does nothing at night.
+func (g *Game) archiveDay() {
+	if !g.isDay() || g.tally == nil {
+		return
+	}
+	if n := len(g.voteHistory); n > 0 && g.voteHistory[n-1].Phase == g.phase {
+		return
+	}
+	g.voteHistory = append(g.voteHistory, &tally.Day{Phase: g.phase, Log: g.tally.Log})
+	g.broadcast(server.VoteHistory, g.voteHistory)
+}
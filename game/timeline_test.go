@@ -0,0 +1,75 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeline(t *testing.T) {
+	assert := assert.New(t)
+	players := []*player.Player{}
+	for i := 0; i < 5; i++ {
+		players = append(players, player.NewPlayer(player.NewMockCommunicator()))
+	}
+	g := NewGame(players[0])
+	for _, p := range players[1:] {
+		g.AddPlayer(p)
+	}
+	require.Nil(t, g.ChooseRoleset("Fast Fiver"))
+	require.Nil(t, g.Start())
+
+	byRole := make(map[string]*player.Player)
+	for _, p := range players {
+		byRole[p.Role.Name] = p
+	}
+	seer, wolf, villager, cultist := byRole["Seer"], byRole["Werewolf"], byRole["Villager"], byRole["Cultist"]
+	villager.Role.SetTinker()
+
+	for _, p := range []*player.Player{seer, villager, byRole["Hunter"]} {
+		require.Nil(t, g.Vote(&player.FingerPoint{From: p, To: cultist}))
+	}
+	require.True(t, g.IsNight())
+	require.Len(t, g.voteHistory, 1, "the day's votes are kept once it's over")
+	assert.Equal(1, g.voteHistory[0].Phase)
+	assert.Len(g.voteHistory[0].Log, 3)
+	require.Nil(t, g.SetNightAction(&player.FingerPoint{From: seer, To: villager}))
+	require.Nil(t, g.SetNightAction(&player.FingerPoint{From: wolf, To: villager}))
+
+	timeline := g.Timeline()
+	votes, views := 0, []*server.TimelineEvent{}
+	var deaths []*server.TimelineEvent
+	for _, e := range timeline {
+		switch e.Type {
+		case server.VoteCast:
+			votes++
+			assert.Equal(1, e.Phase)
+			assert.Equal(cultist.ID, e.To)
+			assert.False(e.Time.IsZero())
+		case server.ViewResult:
+			views = append(views, e)
+		case server.Death:
+			deaths = append(deaths, e)
+		}
+	}
+	assert.Equal(3, votes)
+
+	// the N0 clear, then the night 1 view of the Tinker
+	require.Len(t, views, 2)
+	assert.Equal(0, views[0].Phase)
+	assert.False(views[0].False)
+	assert.Equal(seer.ID, views[1].From)
+	assert.Equal(villager.ID, views[1].To)
+	assert.True(views[1].Hit)
+	assert.True(views[1].False)
+
+	require.Len(t, deaths, 2)
+	assert.Equal(cultist.ID, deaths[0].To)
+	assert.Equal(server.DeathCause(server.Lynched), deaths[0].Cause)
+	assert.Equal(villager.ID, deaths[1].To)
+	assert.Equal(server.DeathCause(server.NightKilled), deaths[1].Cause)
+}
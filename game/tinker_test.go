@@ -0,0 +1,42 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTinkersAreRevealedAtTheEnd(t *testing.T) {
+	assert := assert.New(t)
+	leader := player.NewPlayer(player.NewMockCommunicator())
+	g := NewGame(leader)
+	for i := 0; i < 8; i++ {
+		g.AddPlayer(player.NewPlayer(player.NewMockCommunicator()))
+	}
+	require.Nil(t, g.ChooseRoleset("Tinkered Niner"))
+	require.Nil(t, g.Start())
+
+	var tinker *player.Player
+	for _, p := range g.Snapshot().Players {
+		if p.Role.IsTinker() {
+			assert.Nil(tinker, "only one Tinker")
+			tinker = p
+		}
+	}
+	require.NotNil(t, tinker)
+	assert.Equal(role.Good, tinker.Role.Team)
+
+	g.EndGame(role.Good)
+	for _, r := range g.ToGameOverMessage().Roles {
+		assert.Equal(r.ID == tinker.ID, r.Tinker)
+	}
+	events := g.Timeline()
+	require.NotEmpty(t, events)
+	assert.Equal(server.TimelineEventType(server.TinkerAssigned), events[0].Type)
+	assert.Equal(tinker.ID, events[0].To)
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRolesetWinCondition(t *testing.T) {
+	assert := assert.New(t)
+	leader := player.NewPlayer(player.NewMockCommunicator())
+	g := NewGame(leader)
+	for i := 0; i < 6; i++ {
+		g.AddPlayer(player.NewPlayer(player.NewMockCommunicator()))
+	}
+	require.Nil(t, g.ChooseRoleset("Cleansing Seven"))
+	require.Nil(t, g.Start())
+
+	var wolf, cultist *player.Player
+	others := []*player.Player{}
+	for _, p := range g.Snapshot().Players {
+		switch {
+		case p.Role.IsMaxEvil():
+			wolf = p
+		case p.Role.IsAuxEvil():
+			cultist = p
+		default:
+			others = append(others, p)
+		}
+	}
+
+	// with the wolf dead, the cultist still has to go
+	voteOut(t, g, wolf, others[:4]...)
+	assert.Equal(Running, g.State())
+	assert.True(g.IsDay(), "with nobody left to act, the night passes straight away")
+
+	voteOut(t, g, cultist, others[:3]...)
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+}
+
+func TestMajorityRoleset(t *testing.T) {
+	assert := assert.New(t)
+	leader := player.NewPlayer(player.NewMockCommunicator())
+	g := NewGame(leader)
+	for i := 0; i < 5; i++ {
+		g.AddPlayer(player.NewPlayer(player.NewMockCommunicator()))
+	}
+	require.Nil(t, g.ChooseRoleset("Pack Six"))
+	require.Nil(t, g.Start())
+
+	wolves, villagers := g.AlivePlayersByType()
+	require.Len(t, wolves, 2)
+	voteOut(t, g, villagers[0], wolves[0], wolves[1], villagers[1])
+	for _, w := range wolves {
+		assert.Nil(g.SetNightAction(&player.FingerPoint{From: w, To: villagers[1]}))
+	}
+
+	// parity would hand the wolves the game here, but they need a majority
+	assert.True(g.IsDay())
+	assert.Equal(Running, g.State())
+
+	voteOut(t, g, villagers[2], wolves[0], wolves[1])
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Evil), g.Snapshot().Winner)
+}
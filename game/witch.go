@@ -0,0 +1,107 @@
+package game
+
+import (
+	"log/slog"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+)
+
+// A Potion is what a Witch chooses to do on a night. Each potion can only be
+// used once a game.
+type Potion string
+
+const (
+	// SavePotion keeps its target from dying that night.
+	SavePotion Potion = "save"
+	// PoisonPotion kills its target.
+	PoisonPotion = "poison"
+	// NoPotion saves the Witch's potions for another night.
+	NoPotion = "none"
+)
+
+type potionChoice struct {
+	potion Potion
+	target *player.Player
+}
+
+// hasPotion reports whether p started with potion and hasn't used it yet.
+func (g *Game) hasPotion(p *player.Player, potion Potion) bool {
+	switch potion {
+	case SavePotion:
+		if !p.Role.HasSavePotion() {
+			return false
+		}
+	case PoisonPotion:
+		if !p.Role.HasPoisonPotion() {
+			return false
+		}
+	default:
+		return false
+	}
+	return !g.usedPotions[p.ID][potion]
+}
+
+// hasPotions reports whether p has any potion left, and so has a choice to
+// make each night.
+func (g *Game) hasPotions(p *player.Player) bool {
+	return g.hasPotion(p, SavePotion) || g.hasPotion(p, PoisonPotion)
+}
+
+// choosePotion sets p's potion for tonight, on target, which is ignored for
+// NoPotion. Like a night action, it can be changed until the night ends.
+func (g *Game) choosePotion(p *player.Player, potion Potion, target *player.Player) error {
+	if g.state != Running {
+		return &StateError{NeedState: Running, InState: g.state}
+	}
+	if hunter := g.shooter(); hunter != nil {
+		return &WaitingForShotError{Hunter: hunter}
+	}
+	if !g.isNight() || g.phase == 0 {
+		return &PhaseError{GamePhase: g.phase}
+	}
+	if !p.Role.Alive {
+		return &DeadPlayerError{Player: p, Action: "use a potion"}
+	}
+	if !g.hasPotions(p) || (potion != NoPotion && !g.hasPotion(p, potion)) {
+		return &PotionError{Player: p, Potion: potion}
+	}
+	if potion == NoPotion {
+		target = nil
+	} else if target == nil {
+		return &FingerPointError{FingerPoint: &player.FingerPoint{From: p}}
+	} else if !target.Role.Alive {
+		return &DeadPlayerError{Player: target, Action: "be targeted by a potion"}
+	}
+
+	slog.Info("potion chosen", "witch", p, "potion", potion, "target", target)
+	g.potionChoices[p] = &potionChoice{potion: potion, target: target}
+	g.checkNightActions()
+	return nil
+}
+
+// brewPotions uses tonight's potions, except those of a Witch who was
+// roleblocked, who keeps theirs. It returns who was saved, and the poisonings
+// to be carried out with the night's kills.
+func (g *Game) brewPotions(blocked map[*player.Player]bool) (map[*player.Player]bool, []*player.FingerPoint) {
+	saved := map[*player.Player]bool{}
+	poisonings := []*player.FingerPoint{}
+	for witch, c := range g.potionChoices {
+		if c.potion == NoPotion || blocked[witch] {
+			continue
+		}
+		if g.usedPotions[witch.ID] == nil {
+			g.usedPotions[witch.ID] = make(map[Potion]bool)
+		}
+		g.usedPotions[witch.ID][c.potion] = true
+		switch c.potion {
+		case SavePotion:
+			saved[c.target] = true
+			g.record(&server.TimelineEvent{Type: server.WitchSaved, From: witch.ID, To: c.target.ID})
+		case PoisonPotion:
+			poisonings = append(poisonings, &player.FingerPoint{From: witch, To: c.target})
+			g.record(&server.TimelineEvent{Type: server.WitchPoisoned, From: witch.ID, To: c.target.ID})
+		}
+	}
+	return saved, poisonings
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lastDeath is the cause of p's death from the timeline.
+func lastDeath(g *Game, p *player.Player) server.DeathCause {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, e := range g.timeline {
+		if e.Type == server.Death && e.To == p.ID {
+			return e.Cause
+		}
+	}
+	return ""
+}
+
+func TestRoleblockStopsViews(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	blocker, seer := villagers[0], villagers[1]
+	g.mu.Lock()
+	blocker.Role = role.Roleblocker()
+	seer.Role = role.Seer()
+	g.mu.Unlock()
+
+	voteOut(t, g, villagers[3], blocker, seer, villagers[2])
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[2]}))
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: seer, To: wolf}))
+	assert.True(g.IsNight(), "the night waits for the roleblocker")
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: blocker, To: seer}))
+
+	assert.True(g.IsDay())
+	assert.False(villagers[2].Role.Alive)
+	for _, v := range seer.Views {
+		assert.NotEqual(role.MaxEvilAttribute, v.Attribute, "a blocked seer sees nothing")
+	}
+}
+
+func TestRoleblockStopsTheKill(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	blocker := villagers[0]
+	g.mu.Lock()
+	blocker.Role = role.Roleblocker()
+	g.mu.Unlock()
+
+	voteOut(t, g, villagers[3], blocker, villagers[1], villagers[2])
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[1]}))
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: blocker, To: wolf}))
+
+	assert.True(g.IsDay())
+	assert.True(villagers[1].Role.Alive)
+}
+
+func TestWitchPotions(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	witch := villagers[0]
+	g.mu.Lock()
+	witch.Role = role.Witch()
+	g.mu.Unlock()
+
+	var phaseErr *PhaseError
+	assert.True(errors.As(g.UsePotion(witch, SavePotion, villagers[1]), &phaseErr))
+
+	// the witch saves the wolf's victim
+	voteOut(t, g, villagers[3], witch, villagers[1], villagers[2])
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[1]}))
+	assert.True(g.IsNight(), "the night waits for the witch")
+	var noAction *NoNightActionError
+	assert.True(errors.As(g.SetNightAction(&player.FingerPoint{From: witch, To: wolf}), &noAction))
+	assert.True(g.IsNight(), "only a potion will do")
+	var potionErr *PotionError
+	assert.True(errors.As(g.UsePotion(villagers[1], SavePotion, villagers[1]), &potionErr))
+	assert.True(errors.As(g.UsePotion(witch, "love", villagers[1]), &potionErr))
+	require.Nil(t, g.UsePotion(witch, SavePotion, villagers[1]))
+	assert.True(g.IsDay())
+	assert.True(villagers[1].Role.Alive)
+
+	// the save is gone, but the poison gets the wolf before they can reach
+	// parity
+	voteOut(t, g, villagers[2], witch, villagers[1])
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[1]}))
+	err := g.UsePotion(witch, SavePotion, villagers[1])
+	assert.True(errors.As(err, &potionErr))
+	assert.Equal("noPotion", server.NewErrorMessage("", err).Code)
+	require.Nil(t, g.UsePotion(witch, PoisonPotion, wolf))
+
+	assert.Equal(Finished, g.State())
+	assert.Equal(role.PlayerType(role.Good), g.Snapshot().Winner)
+	assert.Equal(server.DeathCause(server.Poisoned), lastDeath(g, wolf))
+	assert.True(villagers[1].Role.Alive)
+}
+
+func TestWitchWithoutPotionsDoesNotHoldTheNight(t *testing.T) {
+	assert := assert.New(t)
+	g, wolf, villagers := vanillaFiver(t, ModKill)
+	witch := villagers[0]
+	g.mu.Lock()
+	witch.Role = role.Witch()
+	g.usedPotions[witch.ID] = map[Potion]bool{SavePotion: true}
+	g.mu.Unlock()
+
+	// keeping the poison for later still ends the night
+	voteOut(t, g, villagers[3], witch, villagers[1], villagers[2])
+	assert.Nil(g.UsePotion(witch, NoPotion, nil))
+	assert.Nil(g.SetNightAction(&player.FingerPoint{From: wolf, To: villagers[1]}))
+	assert.True(g.IsDay())
+	assert.Equal(server.DeathCause(server.NightKilled), lastDeath(g, villagers[1]))
+
+	g.mu.Lock()
+	g.usedPotions[witch.ID][PoisonPotion] = true
+	assert.NotContains(g.alivePlayersWithNightActions(), witch, "with no potions left there is nothing to wait for")
+	g.mu.Unlock()
+}
@@ -16,10 +16,19 @@ const (
 	Quit
 	ResetGame
 	Awoo
+	Disconnect
+	TransferLeader
+	Kick
+	SetLynchRule
+	Shoot
+	SetRevealMode
+	ChooseLovers
+	UsePotion
 )
 
 type Activity struct {
-	Type  ActivityType
-	From  uuid.UUID
-	Value interface{}
+	Type      ActivityType
+	From      uuid.UUID
+	Value     interface{}
+	RequestID string
 }
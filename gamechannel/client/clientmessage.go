@@ -2,39 +2,111 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
-
-	"github.com/google/uuid"
 )
 
 type MessageType string
 
 const (
-	Awoo        MessageType = "awoo"
-	SetName                 = "setName"
-	SetRoleset              = "setRoleset"
-	Vote                    = "vote"
-	NightAction             = "nightAction"
-	Start                   = "start"
-	Quit                    = "quit"
+	Awoo           MessageType = "awoo"
+	SetName                    = "setName"
+	SetRoleset                 = "setRoleset"
+	Vote                       = "vote"
+	NightAction                = "nightAction"
+	Start                      = "start"
+	Quit                       = "quit"
+	ResetGame                  = "resetGame"
+	TransferLeader             = "transferLeader"
+	Kick                       = "kick"
+	SetLynchRule               = "setLynchRule"
+	Shoot                      = "shoot"
+	SetRevealMode              = "setRevealMode"
+	ChooseLovers               = "chooseLovers"
+	UsePotion                  = "usePotion"
 )
 
+const (
+	// LegacyVersion is used for messages sent without a version. They carry
+	// their fields at the top level of the message instead of in a payload.
+	LegacyVersion = 0
+	// ProtocolVersion is the newest version of the protocol this package speaks.
+	ProtocolVersion = 1
+)
+
+// An Envelope is the wire format of every client message. The shape of
+// Payload depends on Type; see payloads.
+type Envelope struct {
+	Version   int             `json:"version"`
+	RequestID string          `json:"requestId,omitempty"`
+	Type      MessageType     `json:"messageType"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// A Message is a client message that has been decoded and validated. Payload
+// is one of the payload structs in this package, or nil for message types
+// that don't carry one.
 type Message struct {
-	Type       MessageType `json:"messageType"`
-	PlayerName string      `json:"playerName"`
-	Roleset    string      `json:"roleset"`
-	Target     uuid.UUID   `json:"target"`
+	Version   int
+	RequestID string
+	Type      MessageType
+	Payload   Payload
+}
+
+func (m *Message) String() string {
+	return fmt.Sprintf("%s (v%d, request %q): %+v", m.Type, m.Version, m.RequestID, m.Payload)
 }
 
+// Decode parses a raw client message, returning a *DecodeError if it is
+// malformed, uses an unsupported version, is of an unknown type, or has an
+// invalid payload.
 func Decode(raw []byte) (*Message, error) {
-	var message Message
-	err := json.Unmarshal(raw, &message)
-	if err != nil {
+	var e Envelope
+	if err := json.Unmarshal(raw, &e); err != nil {
 		slog.Error("error decoding client message", "error", err, "raw", string(raw[:]))
-		return nil, err
+		return nil, &DecodeError{Kind: MalformedMessage, Reason: err.Error()}
+	}
+
+	payload := e.Payload
+	switch e.Version {
+	case LegacyVersion:
+		payload = raw
+	case ProtocolVersion:
+	default:
+		return nil, &DecodeError{
+			Kind:      UnsupportedVersion,
+			RequestID: e.RequestID,
+			Reason:    fmt.Sprintf("version %d is not supported, use %d", e.Version, ProtocolVersion),
+		}
+	}
+
+	if e.Type == "" {
+		return nil, &DecodeError{Kind: MalformedMessage, RequestID: e.RequestID, Reason: "messageType is required"}
+	}
+	newPayload, ok := payloads[e.Type]
+	if !ok {
+		return nil, &DecodeError{Kind: UnknownMessageType, RequestID: e.RequestID, Reason: fmt.Sprintf("unknown messageType %q", e.Type)}
+	}
+
+	m := &Message{
+		Version:   e.Version,
+		RequestID: e.RequestID,
+		Type:      e.Type,
+	}
+	if newPayload == nil {
+		return m, nil
+	}
+
+	p := newPayload()
+	if len(payload) == 0 {
+		return nil, &DecodeError{Kind: InvalidPayload, RequestID: e.RequestID, Reason: fmt.Sprintf("%s requires a payload", e.Type)}
+	}
+	if err := json.Unmarshal(payload, p); err != nil {
+		return nil, &DecodeError{Kind: InvalidPayload, RequestID: e.RequestID, Reason: err.Error()}
 	}
-	if message.Type == "" {
-		slog.Error("message did not decode properly", "message", message, "raw", string(raw[:]))
+	if err := p.Validate(); err != nil {
+		return nil, &DecodeError{Kind: InvalidPayload, RequestID: e.RequestID, Reason: err.Error()}
 	}
-	return &message, nil
+	m.Payload = p
+	return m, nil
 }
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeVersioned(t *testing.T) {
+	assert := assert.New(t)
+	target := uuid.New()
+
+	m, err := Decode([]byte(`{"version":1,"requestId":"abc","messageType":"vote","payload":{"target":"` + target.String() + `"}}`))
+
+	assert.Nil(err)
+	assert.Equal(ProtocolVersion, m.Version)
+	assert.Equal("abc", m.RequestID)
+	assert.Equal(MessageType(Vote), m.Type)
+	assert.Equal(&TargetPayload{Target: target}, m.Payload)
+}
+
+func TestDecodeLegacy(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := Decode([]byte(`{"messageType":"setName","playerName":"  Sigafoos "}`))
+
+	assert.Nil(err)
+	assert.Equal(LegacyVersion, m.Version)
+	assert.Empty(m.RequestID)
+	assert.Equal(&SetNamePayload{PlayerName: "Sigafoos"}, m.Payload)
+}
+
+func TestDecodeWithoutPayload(t *testing.T) {
+	m, err := Decode([]byte(`{"version":1,"requestId":"1","messageType":"start"}`))
+
+	assert.Nil(t, err)
+	assert.Nil(t, m.Payload)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	cases := map[string]struct {
+		raw       string
+		code      ErrorCode
+		requestID string
+	}{
+		"not json":            {`{"messageType":`, MalformedMessage, ""},
+		"missing type":        {`{"version":1,"requestId":"1"}`, MalformedMessage, "1"},
+		"future version":      {`{"version":9,"requestId":"2","messageType":"start"}`, UnsupportedVersion, "2"},
+		"unknown type":        {`{"version":1,"requestId":"3","messageType":"eat"}`, UnknownMessageType, "3"},
+		"missing payload":     {`{"version":1,"requestId":"4","messageType":"vote"}`, InvalidPayload, "4"},
+		"missing target":      {`{"version":1,"requestId":"5","messageType":"vote","payload":{}}`, InvalidPayload, "5"},
+		"bad target":          {`{"version":1,"requestId":"6","messageType":"nightAction","payload":{"target":"nope"}}`, InvalidPayload, "6"},
+		"empty name":          {`{"version":1,"requestId":"7","messageType":"setName","payload":{"playerName":"  "}}`, InvalidPayload, "7"},
+		"long name":           {`{"version":1,"requestId":"8","messageType":"setName","payload":{"playerName":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}}`, InvalidPayload, "8"},
+		"empty roleset":       {`{"version":1,"requestId":"9","messageType":"setRoleset","payload":{"roleset":""}}`, InvalidPayload, "9"},
+		"legacy empty target": {`{"messageType":"vote"}`, InvalidPayload, ""},
+		"one lover":           {`{"version":1,"requestId":"10","messageType":"chooseLovers","payload":{"first":"9d3b5a4e-2b1a-4c1e-8f3a-1a2b3c4d5e6f"}}`, InvalidPayload, "10"},
+		"self love":           {`{"version":1,"requestId":"11","messageType":"chooseLovers","payload":{"first":"9d3b5a4e-2b1a-4c1e-8f3a-1a2b3c4d5e6f","second":"9d3b5a4e-2b1a-4c1e-8f3a-1a2b3c4d5e6f"}}`, InvalidPayload, "11"},
+		"empty potion":        {`{"version":1,"requestId":"12","messageType":"usePotion","payload":{"potion":""}}`, InvalidPayload, "12"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := Decode([]byte(c.raw))
+
+			assert.Nil(t, m)
+			var de *DecodeError
+			if assert.True(t, errors.As(err, &de)) {
+				assert.Equal(t, c.code, de.Kind)
+				assert.Equal(t, string(c.code), de.Code())
+				assert.Equal(t, c.requestID, de.RequestID)
+			}
+		})
+	}
+}
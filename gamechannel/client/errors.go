@@ -0,0 +1,31 @@
+package client
+
+import (
+	"fmt"
+)
+
+type ErrorCode string
+
+const (
+	MalformedMessage   ErrorCode = "malformedMessage"
+	UnsupportedVersion           = "unsupportedVersion"
+	UnknownMessageType           = "unknownMessageType"
+	InvalidPayload               = "invalidPayload"
+)
+
+// A DecodeError is returned when a client message cannot be decoded. If the
+// envelope could be read, RequestID is set so the reply can reference it.
+type DecodeError struct {
+	Kind      ErrorCode
+	RequestID string
+	Reason    string
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("client: %s: %s", e.Kind, e.Reason)
+}
+
+// Code returns the machine-readable error code.
+func (e *DecodeError) Code() string {
+	return string(e.Kind)
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxNameLength is the longest name, in characters, a player can choose.
+const MaxNameLength = 32
+
+// A Payload is the type-specific body of a client message.
+type Payload interface {
+	Validate() error
+}
+
+// payloads maps each message type to a constructor for its payload. Types
+// without a payload map to nil.
+var payloads = map[MessageType]func() Payload{
+	Awoo:           nil,
+	SetName:        func() Payload { return &SetNamePayload{} },
+	SetRoleset:     func() Payload { return &SetRolesetPayload{} },
+	Vote:           func() Payload { return &TargetPayload{} },
+	NightAction:    func() Payload { return &TargetPayload{} },
+	Start:          nil,
+	Quit:           nil,
+	ResetGame:      nil,
+	TransferLeader: func() Payload { return &TargetPayload{} },
+	Kick:           func() Payload { return &TargetPayload{} },
+	SetLynchRule:   func() Payload { return &SetLynchRulePayload{} },
+	Shoot:          func() Payload { return &TargetPayload{} },
+	SetRevealMode:  func() Payload { return &SetRevealModePayload{} },
+	ChooseLovers:   func() Payload { return &LoversPayload{} },
+	UsePotion:      func() Payload { return &PotionPayload{} },
+}
+
+type SetNamePayload struct {
+	PlayerName string `json:"playerName"`
+}
+
+func (p *SetNamePayload) Validate() error {
+	p.PlayerName = strings.TrimSpace(p.PlayerName)
+	if p.PlayerName == "" {
+		return fmt.Errorf("playerName is required")
+	}
+	if len([]rune(p.PlayerName)) > MaxNameLength {
+		return fmt.Errorf("playerName must be at most %d characters", MaxNameLength)
+	}
+	return nil
+}
+
+type SetRolesetPayload struct {
+	Roleset string `json:"roleset"`
+}
+
+func (p *SetRolesetPayload) Validate() error {
+	if p.Roleset == "" {
+		return fmt.Errorf("roleset is required")
+	}
+	return nil
+}
+
+// A TargetPayload is used by any message that points at another player,
+// such as a vote or a night action.
+type TargetPayload struct {
+	Target uuid.UUID `json:"target"`
+}
+
+func (p *TargetPayload) Validate() error {
+	if p.Target == uuid.Nil {
+		return fmt.Errorf("target is required")
+	}
+	return nil
+}
+
+// SetLynchRulePayload chooses how many votes a lynch takes. DayLength is in
+// seconds, and only used by rules with a deadline; 0 keeps the default.
+type SetLynchRulePayload struct {
+	Rule      string `json:"rule"`
+	DayLength int    `json:"dayLength,omitempty"`
+}
+
+func (p *SetLynchRulePayload) Validate() error {
+	if p.Rule == "" {
+		return fmt.Errorf("rule is required")
+	}
+	if p.DayLength < 0 {
+		return fmt.Errorf("dayLength can't be negative")
+	}
+	return nil
+}
+
+type SetRevealModePayload struct {
+	Mode string `json:"mode"`
+}
+
+func (p *SetRevealModePayload) Validate() error {
+	if p.Mode == "" {
+		return fmt.Errorf("mode is required")
+	}
+	return nil
+}
+
+// PotionPayload is a Witch's choice for the night: "save" or "poison" on
+// Target, or "none" to keep their potions, in which case Target is left out.
+type PotionPayload struct {
+	Potion string    `json:"potion"`
+	Target uuid.UUID `json:"target,omitempty"`
+}
+
+func (p *PotionPayload) Validate() error {
+	if p.Potion == "" {
+		return fmt.Errorf("potion is required")
+	}
+	return nil
+}
+
+// LoversPayload is Cupid's choice of the two players to link.
+type LoversPayload struct {
+	First  uuid.UUID `json:"first"`
+	Second uuid.UUID `json:"second"`
+}
+
+func (p *LoversPayload) Validate() error {
+	if p.First == uuid.Nil || p.Second == uuid.Nil {
+		return fmt.Errorf("first and second are required")
+	}
+	if p.First == p.Second {
+		return fmt.Errorf("first and second must be different players")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package gamechannel
+
+// These are the query parameters a client connects to the game websocket
+// with, which say which seat it takes.
+const (
+	// PasswordParam is the query parameter used to join an existing game.
+	PasswordParam = "password"
+	// PlayerParam is the query parameter used to reconnect to a seat.
+	PlayerParam = "id"
+	// TokenParam is the query parameter carrying the session token that
+	// proves a reconnecting client holds the seat.
+	TokenParam = "token"
+	// AccountParam and KeyParam sign the connecting player in to an account.
+	AccountParam = "account"
+	KeyParam     = "key"
+)
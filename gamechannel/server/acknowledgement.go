@@ -0,0 +1,8 @@
+package server
+
+// An Acknowledgement tells a client that its request was valid and has been
+// passed along to the game.
+type Acknowledgement struct {
+	RequestID   string `json:"requestId"`
+	MessageType string `json:"messageType"`
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"errors"
+)
+
+// GenericErrorCode is used for errors that don't carry a code of their own.
+const GenericErrorCode = "error"
+
+// A CodedError is an error with a stable, machine-readable code.
+type CodedError interface {
+	error
+	Code() string
+}
+
+// A FieldedError is an error that can describe itself with structured fields,
+// such as the state a game was in or the player an action targeted.
+type FieldedError interface {
+	error
+	Fields() map[string]interface{}
+}
+
+// An ErrorMessage is the payload of an Error message. If it was caused by a
+// client request, RequestID references it.
+type ErrorMessage struct {
+	RequestID string                 `json:"requestId,omitempty"`
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+func NewErrorMessage(requestID string, err error) *ErrorMessage {
+	m := &ErrorMessage{
+		RequestID: requestID,
+		Code:      GenericErrorCode,
+		Message:   err.Error(),
+	}
+	var coded CodedError
+	if errors.As(err, &coded) {
+		m.Code = coded.Code()
+	}
+	var fielded FieldedError
+	if errors.As(err, &fielded) {
+		m.Fields = fielded.Fields()
+	}
+	return m
+}
@@ -2,9 +2,14 @@ package server
 
 import (
 	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
 )
 
+// A GameOverMessage says which team won and lists every player who won,
+// which may include neutral players who won on their own terms.
 type GameOverMessage struct {
-	Winner role.PlayerType   `json:"winner"`
-	Roles  []*RevealedPlayer `json:"roles"`
+	Winner  role.PlayerType   `json:"winner"`
+	Winners []uuid.UUID       `json:"winners"`
+	Roles   []*RevealedPlayer `json:"roles"`
 }
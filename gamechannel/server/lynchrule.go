@@ -0,0 +1,8 @@
+package server
+
+// A LynchRule is the payload of LynchRuleSet. DayLength, in seconds, is only
+// set for rules that end the day at a deadline.
+type LynchRule struct {
+	Rule      string `json:"rule"`
+	DayLength int    `json:"dayLength,omitempty"`
+}
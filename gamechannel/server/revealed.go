@@ -6,8 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// A RevealedPlayer is a player's true role, shown once the game is over.
+// Tinker is set if the role was a Tinker, which the player wasn't told, and
+// Lover if they were one of the lovers.
 type RevealedPlayer struct {
-	ID   uuid.UUID  `json:"id"`
-	Name string     `json:"name"`
-	Role *role.Role `json:"role"`
+	ID     uuid.UUID  `json:"id"`
+	Name   string     `json:"name"`
+	Role   *role.Role `json:"role"`
+	Tinker bool       `json:"tinker,omitempty"`
+	Lover  bool       `json:"lover,omitempty"`
 }
@@ -0,0 +1,14 @@
+package server
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A RevengeShotPrompt is the payload of RevengeShot, sent to everyone when a
+// hunter dies. The game waits until the hunter shoots or Deadline passes.
+type RevengeShotPrompt struct {
+	Hunter   uuid.UUID `json:"hunter"`
+	Deadline time.Time `json:"deadline"`
+}
@@ -7,23 +7,33 @@ import (
 type MessageType string
 
 const (
-	Awoo            MessageType = "awoo"
-	IDSet                       = "idSet"
-	NameSet                     = "nameSet"
-	PlayerJoin                  = "playerJoin"
-	PlayerLeave                 = "playerLeave"
-	AlivePlayerList             = "alivePlayerList"
-	RolesetList                 = "rolesetList"
-	RolesetSelected             = "rolesetSelected"
-	LeaderSet                   = "leaderSet"
-	Password                    = "password"
-	TallyChanged                = "tallyChanged"
-	RoleAssigned                = "roleAssigned"
-	PhaseChanged                = "phaseChanged"
-	View                        = "view"
-	PlayerKilled                = "playerKilled"
-	GameOver                    = "gameOver"
-	Error                       = "error"
+	Awoo              MessageType = "awoo"
+	IDSet                         = "idSet"
+	NameSet                       = "nameSet"
+	PlayerJoin                    = "playerJoin"
+	PlayerLeave                   = "playerLeave"
+	AlivePlayerList               = "alivePlayerList"
+	RolesetList                   = "rolesetList"
+	RolesetSelected               = "rolesetSelected"
+	LeaderSet                     = "leaderSet"
+	Password                      = "password"
+	TallyChanged                  = "tallyChanged"
+	RoleAssigned                  = "roleAssigned"
+	PhaseChanged                  = "phaseChanged"
+	View                          = "view"
+	PlayerKilled                  = "playerKilled"
+	GameOver                      = "gameOver"
+	Error                         = "error"
+	Ack                           = "ack"
+	GameReset                     = "gameReset"
+	Kicked                        = "kicked"
+	PlayerSubstituted             = "playerSubstituted"
+	SessionToken                  = "sessionToken"
+	Timeline                      = "timeline"
+	VoteHistory                   = "voteHistory"
+	LynchRuleSet                  = "lynchRuleSet"
+	RevengeShot                   = "revengeShot"
+	RevealModeSet                 = "revealModeSet"
 )
 
 type Message struct {
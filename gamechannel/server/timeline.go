@@ -0,0 +1,54 @@
+package server
+
+import (
+	"time"
+
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
+)
+
+type TimelineEventType string
+
+const (
+	VoteCast       TimelineEventType = "vote"
+	VoteWithdrawn                    = "unvote"
+	NightAction                      = "nightAction"
+	ViewResult                       = "view"
+	Death                            = "death"
+	HunterShot                       = "shot"
+	TinkerAssigned                   = "tinker"
+	LoversLinked                     = "lovers"
+	Roleblocked                      = "roleblock"
+	WitchSaved                       = "save"
+	WitchPoisoned                    = "poison"
+)
+
+type DeathCause string
+
+const (
+	Lynched     DeathCause = "lynched"
+	NightKilled            = "nightKilled"
+	ModKilled              = "modKilled"
+	Shot                   = "shot"
+	Heartbreak             = "heartbreak"
+	Poisoned               = "poisoned"
+)
+
+// A TimelineEvent is one thing that happened in a game, for the timeline
+// shown once it is over. From is who acted, if anyone, and To who they acted
+// on; for a death, To is who died.
+//
+// A view's Hit is what the viewer was told. False is set if that was wrong,
+// because they viewed a Tinker.
+type TimelineEvent struct {
+	Type      TimelineEventType `json:"type"`
+	Phase     int               `json:"phase"`
+	Time      time.Time         `json:"time"`
+	From      uuid.UUID         `json:"from"`
+	To        uuid.UUID         `json:"to"`
+	Attribute role.Attribute    `json:"attribute,omitempty"`
+	Hit       bool              `json:"hit,omitempty"`
+	False     bool              `json:"false,omitempty"`
+	Cause     DeathCause        `json:"cause,omitempty"`
+}
@@ -0,0 +1,85 @@
+// Package history serves finished games over HTTP, so that they can be
+// browsed and their timelines replayed after the players have gone.
+package history
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/awoo-detat/werewolf/game"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
+)
+
+// A Source has the results of finished games, as an account.Store does.
+type Source interface {
+	Results() []*game.Result
+	Result(id uuid.UUID) (*game.Result, bool)
+}
+
+// A Summary is a game as it is listed, without its timeline.
+type Summary struct {
+	ID       uuid.UUID       `json:"id"`
+	Roleset  string          `json:"roleset"`
+	Winner   role.PlayerType `json:"winner"`
+	Finished time.Time       `json:"finished"`
+	Players  []string        `json:"players"`
+}
+
+func summarize(r *game.Result) *Summary {
+	s := &Summary{
+		ID:       r.ID,
+		Roleset:  r.Roleset,
+		Winner:   r.Winner,
+		Finished: r.Finished,
+		Players:  []string{},
+	}
+	for _, p := range r.Players {
+		s.Players = append(s.Players, p.Name)
+	}
+	return s
+}
+
+// A Handler serves its Source, mounted at /games/:
+//
+//	GET /games/      lists every game, newest first
+//	GET /games/{id}  returns a game's full result, timeline included
+type Handler struct {
+	Source Source
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/games"), "/")
+	if path == "" {
+		results := h.Source.Results()
+		summaries := make([]*Summary, 0, len(results))
+		for i := len(results) - 1; i >= 0; i-- {
+			summaries = append(summaries, summarize(results[i]))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summaries)
+		return
+	}
+
+	id, err := uuid.Parse(path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	result, ok := h.Source.Result(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
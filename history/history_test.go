@@ -0,0 +1,87 @@
+package history
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/game"
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type results []*game.Result
+
+func (r results) Results() []*game.Result { return r }
+
+func (r results) Result(id uuid.UUID) (*game.Result, bool) {
+	for _, res := range r {
+		if res.ID == id {
+			return res, true
+		}
+	}
+	return nil, false
+}
+
+func get(h http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	assert := assert.New(t)
+	alice := &game.PlayerResult{PlayerID: uuid.New(), Name: "alice"}
+	old := &game.Result{ID: uuid.New(), Roleset: "Vanilla Fiver", Finished: time.Now().Add(-time.Hour), Players: []*game.PlayerResult{alice}}
+	recent := &game.Result{ID: uuid.New(), Roleset: "Fast Fiver", Finished: time.Now(), Players: []*game.PlayerResult{alice}, Timeline: []*server.TimelineEvent{
+		{Type: server.Death, Phase: 1, To: alice.PlayerID, Cause: server.Lynched},
+	}}
+	h := &Handler{Source: results{old, recent}}
+
+	w := get(h, "/games/")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list []*Summary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 2)
+	assert.Equal(recent.ID, list[0].ID, "newest first")
+	assert.Equal([]string{"alice"}, list[1].Players)
+
+	w = get(h, "/games/"+recent.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	var got game.Result
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	require.Len(t, got.Timeline, 1)
+	assert.Equal(server.DeathCause(server.Lynched), got.Timeline[0].Cause)
+
+	assert.Equal(http.StatusNotFound, get(h, "/games/"+uuid.New().String()).Code)
+	assert.Equal(http.StatusNotFound, get(h, "/games/nope").Code)
+}
+
+func TestMemory(t *testing.T) {
+	assert := assert.New(t)
+	m := &Memory{Keep: 2}
+	first := &game.Result{ID: uuid.New()}
+	second := &game.Result{ID: uuid.New()}
+	third := &game.Result{ID: uuid.New()}
+
+	m.Record(first)
+	m.Record(second)
+	assert.Equal([]*game.Result{first, second}, m.Results())
+	got, ok := m.Result(first.ID)
+	assert.True(ok)
+	assert.Equal(first, got)
+
+	// the oldest goes once it's full
+	m.Record(third)
+	assert.Equal([]*game.Result{second, third}, m.Results())
+	_, ok = m.Result(first.ID)
+	assert.False(ok)
+
+	w := get(&Handler{Source: m}, "/games/"+third.ID.String())
+	assert.Equal(http.StatusOK, w.Code)
+}
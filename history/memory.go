@@ -0,0 +1,55 @@
+package history
+
+import (
+	"sync"
+
+	"github.com/awoo-detat/werewolf/game"
+
+	"github.com/google/uuid"
+)
+
+// DefaultKeep is how many results a Memory holds, unless told otherwise.
+const DefaultKeep = 1000
+
+// A Memory is a Source that keeps results in memory, for a server with no
+// account.Store to keep them in. It holds the most recent Keep results, or
+// DefaultKeep if Keep is 0, and forgets them all when the server stops. It is
+// safe for concurrent use.
+type Memory struct {
+	Keep    int
+	mu      sync.Mutex
+	results []*game.Result
+}
+
+// Record keeps r, forgetting the oldest result if the Memory is full.
+func (m *Memory) Record(r *game.Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keep := m.Keep
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	m.results = append(m.results, r)
+	if over := len(m.results) - keep; over > 0 {
+		m.results = append([]*game.Result{}, m.results[over:]...)
+	}
+}
+
+// Results returns every result kept, oldest first.
+func (m *Memory) Results() []*game.Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*game.Result{}, m.results...)
+}
+
+// Result returns the result with the given ID, if it is still kept.
+func (m *Memory) Result(id uuid.UUID) (*game.Result, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.results {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,65 @@
+package hub
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type GameNotFoundError struct {
+	Password string
+}
+
+func (e *GameNotFoundError) Error() string {
+	return fmt.Sprintf("hub: no game with password %q", e.Password)
+}
+
+func (e *GameNotFoundError) Code() string {
+	return "gameNotFound"
+}
+
+type BannedError struct {
+	AccountID uuid.UUID
+}
+
+func (e *BannedError) Error() string {
+	return fmt.Sprintf("hub: account %s has been kicked from this game", e.AccountID)
+}
+
+func (e *BannedError) Code() string {
+	return "banned"
+}
+
+type SeatNotFoundError struct {
+	ID string
+}
+
+func (e *SeatNotFoundError) Error() string {
+	return fmt.Sprintf("hub: no seat for player %q", e.ID)
+}
+
+func (e *SeatNotFoundError) Code() string {
+	return "seatNotFound"
+}
+
+type InvalidTokenError struct {
+	ID string
+}
+
+func (e *InvalidTokenError) Error() string {
+	return fmt.Sprintf("hub: wrong session token for player %q", e.ID)
+}
+
+func (e *InvalidTokenError) Code() string {
+	return "invalidToken"
+}
+
+type AccountsDisabledError struct{}
+
+func (e *AccountsDisabledError) Error() string {
+	return "hub: this server doesn't have accounts"
+}
+
+func (e *AccountsDisabledError) Code() string {
+	return "accountsDisabled"
+}
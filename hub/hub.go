@@ -0,0 +1,271 @@
+// Package hub is the websocket entry point for games: it creates games,
+// seats players in them, and hands dropped players back their seats.
+package hub
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/awoo-detat/werewolf/account"
+	"github.com/awoo-detat/werewolf/game"
+	"github.com/awoo-detat/werewolf/gamechannel"
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/history"
+	"github.com/awoo-detat/werewolf/player"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// A Hub serves the game websocket. Connecting with no parameters creates a
+// new game led by the connecting player; connecting with a password joins
+// that game; connecting with a player ID and session token reconnects to that
+// player's seat.
+// Games are forgotten once they stop.
+//
+// Games call back into the hub, ie when their password changes, while holding
+// their own lock, so the hub never calls into a game while holding its lock.
+//
+// Every finished game is recorded in History, if it is set, and in Accounts,
+// if that is. With Accounts set, any of these can also sign in to an account, which gives the player its name and links
+// them to the games they play. A player signed
+// in to the account that held a seat can take it back without a token, ie from
+// another device.
+type Hub struct {
+	Upgrader websocket.Upgrader
+	Accounts *account.Store
+	History  *history.Memory
+	mu       sync.Mutex
+	games    map[string]*game.Game
+	seats    map[uuid.UUID]*game.Game
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func New() *Hub {
+	h := &Hub{
+		games: make(map[string]*game.Game),
+		seats: make(map[uuid.UUID]*game.Game),
+	}
+	h.ctx, h.cancel = context.WithCancel(context.Background())
+	return h
+}
+
+// Close stops every game in the hub.
+func (h *Hub) Close() {
+	h.cancel()
+}
+
+// Game returns the game with the given password, if there is one.
+func (h *Hub) Game(password string) (*game.Game, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	g, ok := h.games[password]
+	return g, ok
+}
+
+// rekey files g under its new password, once it has changed from its old
+// one, ie after a kick.
+func (h *Hub) rekey(g *game.Game, from, to string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.games[from] == g {
+		delete(h.games, from)
+	}
+	h.games[to] = g
+}
+
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		slog.Warn("hub: error upgrading connection", "error", err)
+		return
+	}
+
+	query := r.URL.Query()
+	addr := remoteHost(r)
+	acct, err := h.signIn(query)
+	if err == nil {
+		switch {
+		case query.Has(gamechannel.PlayerParam):
+			err = h.reconnect(conn, query.Get(gamechannel.PlayerParam), query.Get(gamechannel.TokenParam), acct)
+		case query.Has(gamechannel.PasswordParam):
+			err = h.join(conn, addr, query.Get(gamechannel.PasswordParam), acct)
+		default:
+			h.create(conn, addr, acct)
+		}
+	}
+	if err != nil {
+		slog.Warn("hub: rejecting connection", "error", err)
+		reject(conn, err)
+	}
+}
+
+func (h *Hub) create(conn *websocket.Conn, addr string, acct *account.Profile) {
+	p := player.NewPlayer(conn)
+	p.Addr = addr
+	setAccount(p, acct)
+	g := game.NewGameContext(h.ctx, p)
+	if h.Accounts != nil || h.History != nil {
+		g.OnGameOver = h.recordGame
+	}
+	g.OnPasswordChange = func(from, to string) { h.rekey(g, from, to) }
+	password := g.Password()
+
+	h.mu.Lock()
+	if _, ok := h.games[password]; ok {
+		slog.Warn("hub: password collision, replacing game", "password", password)
+	}
+	h.games[password] = g
+	h.seats[p.ID] = g
+	h.mu.Unlock()
+
+	slog.Info("hub: game created", "game", g.ID, "leader", p)
+	go h.forget(g)
+	go p.Play()
+}
+
+// forget removes a game and its seats from the hub once it stops.
+func (h *Hub) forget(g *game.Game) {
+	<-g.Done()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for password, filed := range h.games {
+		if filed == g {
+			delete(h.games, password)
+		}
+	}
+	for id, seated := range h.seats {
+		if seated == g {
+			delete(h.seats, id)
+		}
+	}
+	slog.Info("hub: game removed", "game", g.ID)
+}
+
+func (h *Hub) join(conn *websocket.Conn, addr, password string, acct *account.Profile) error {
+	g, ok := h.Game(password)
+	if !ok {
+		return &GameNotFoundError{Password: password}
+	}
+	accountID := uuid.Nil
+	if acct != nil {
+		accountID = acct.ID
+	}
+	if g.IsBanned(accountID) {
+		return &BannedError{AccountID: accountID}
+	}
+	if seat, ok := g.Substitute(addr, accountID); ok {
+		slog.Info("hub: substitute joined", "game", g.ID, "player", seat)
+		seat.Replace(conn)
+		return nil
+	}
+	p := player.NewPlayer(conn)
+	p.Addr = addr
+	setAccount(p, acct)
+	if err := g.AddPlayer(p); err != nil {
+		// p's writer owns the socket by now, so the rejection goes through it
+		slog.Warn("hub: rejecting player", "game", g.ID, "error", err)
+		p.SendError("", err)
+		p.Close()
+		return nil
+	}
+
+	h.mu.Lock()
+	h.seats[p.ID] = g
+	h.mu.Unlock()
+
+	slog.Info("hub: player joined", "game", g.ID, "player", p)
+	go p.Play()
+	return nil
+}
+
+func (h *Hub) reconnect(conn *websocket.Conn, rawID, token string, acct *account.Profile) error {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return &SeatNotFoundError{ID: rawID}
+	}
+	h.mu.Lock()
+	g, ok := h.seats[id]
+	h.mu.Unlock()
+	if !ok {
+		return &SeatNotFoundError{ID: rawID}
+	}
+	p, ok := g.Player(id)
+	if !ok {
+		return &SeatNotFoundError{ID: rawID}
+	}
+	if acct != nil && token == "" {
+		if g.AccountID(p) != acct.ID {
+			return &InvalidTokenError{ID: rawID}
+		}
+		p.Replace(conn)
+		return nil
+	}
+	if err := p.Reconnect(conn, token); err != nil {
+		return &InvalidTokenError{ID: rawID}
+	}
+	return nil
+}
+
+// signIn returns the account a connection signs in to, or nil if it doesn't.
+func (h *Hub) signIn(query url.Values) (*account.Profile, error) {
+	if !query.Has(gamechannel.AccountParam) {
+		return nil, nil
+	}
+	if h.Accounts == nil {
+		return nil, &AccountsDisabledError{}
+	}
+	id, err := uuid.Parse(query.Get(gamechannel.AccountParam))
+	if err != nil {
+		return nil, &account.UnauthorizedError{}
+	}
+	return h.Accounts.Authenticate(id, query.Get(gamechannel.KeyParam))
+}
+
+// setAccount gives a new player the identity of the account they signed in
+// to, if any.
+func setAccount(p *player.Player, acct *account.Profile) {
+	if acct == nil {
+		return
+	}
+	p.AccountID = acct.ID
+	p.SetName(acct.Name)
+	p.Message(server.NameSet, acct.Name)
+}
+
+func (h *Hub) recordGame(r *game.Result) {
+	if h.History != nil {
+		h.History.Record(r)
+	}
+	if h.Accounts == nil {
+		return
+	}
+	if err := h.Accounts.RecordGame(r); err != nil {
+		slog.Error("hub: error recording game", "game", r.GameID, "error", err)
+	}
+}
+
+// remoteHost is the host part of the address a request came from. Ports are
+// dropped, since a client gets a new one each time it connects.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// reject tells a connection why it can't be seated, then closes it.
+func reject(conn *websocket.Conn, err error) {
+	m, encodeErr := server.NewMessage(server.Error, server.NewErrorMessage("", err))
+	if encodeErr == nil {
+		conn.WriteMessage(websocket.TextMessage, m)
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()))
+	conn.Close()
+}
@@ -0,0 +1,177 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/account"
+	"github.com/awoo-detat/werewolf/game"
+	"github.com/awoo-detat/werewolf/gamechannel"
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/history"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serve starts h on a test server and returns its websocket URL.
+func serve(t *testing.T, h *Hub) string {
+	s := httptest.NewServer(h)
+	t.Cleanup(func() {
+		s.Close()
+		h.Close()
+	})
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func dial(t *testing.T, base string, query url.Values) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(base+"?"+query.Encode(), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// expect reads from conn until a message of type mt arrives, and decodes its
+// payload into v.
+func expect(t *testing.T, conn *websocket.Conn, mt server.MessageType, v interface{}) {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, raw, err := conn.ReadMessage()
+		require.NoError(t, err, fmt.Sprintf("waiting for %s", mt))
+		var m struct {
+			Type    server.MessageType `json:"messageType"`
+			Payload json.RawMessage    `json:"payload"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &m))
+		if m.Type == mt {
+			require.NoError(t, json.Unmarshal(m.Payload, v))
+			return
+		}
+	}
+}
+
+// rejected checks that conn is told why with code, then closed.
+func rejected(t *testing.T, conn *websocket.Conn, code string) {
+	t.Helper()
+	var e server.ErrorMessage
+	expect(t, conn, server.Error, &e)
+	assert.Equal(t, code, e.Code)
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), err)
+}
+
+func TestCreate(t *testing.T) {
+	h := New()
+	base := serve(t, h)
+
+	conn := dial(t, base, url.Values{})
+	var id uuid.UUID
+	expect(t, conn, server.IDSet, &id)
+	var password string
+	expect(t, conn, server.Password, &password)
+
+	g, ok := h.Game(password)
+	require.True(t, ok)
+	leader := g.Snapshot().Leader
+	require.NotNil(t, leader)
+	assert.Equal(t, id, leader.ID)
+}
+
+func TestJoinWithWrongPassword(t *testing.T) {
+	base := serve(t, New())
+	leader := dial(t, base, url.Values{})
+	var password string
+	expect(t, leader, server.Password, &password)
+
+	conn := dial(t, base, url.Values{gamechannel.PasswordParam: {password + " nope"}})
+	rejected(t, conn, "gameNotFound")
+
+	conn = dial(t, base, url.Values{gamechannel.PasswordParam: {password}})
+	var id uuid.UUID
+	expect(t, conn, server.IDSet, &id)
+	assert.NotEqual(t, uuid.Nil, id)
+}
+
+func TestReconnectWithBadToken(t *testing.T) {
+	base := serve(t, New())
+	conn := dial(t, base, url.Values{})
+	var id uuid.UUID
+	expect(t, conn, server.IDSet, &id)
+	var token string
+	expect(t, conn, server.SessionToken, &token)
+
+	bad := dial(t, base, url.Values{gamechannel.PlayerParam: {id.String()}, gamechannel.TokenParam: {token + "x"}})
+	rejected(t, bad, "invalidToken")
+	unknown := dial(t, base, url.Values{gamechannel.PlayerParam: {uuid.NewString()}, gamechannel.TokenParam: {token}})
+	rejected(t, unknown, "seatNotFound")
+
+	// the real token still works, and is replaced
+	again := dial(t, base, url.Values{gamechannel.PlayerParam: {id.String()}, gamechannel.TokenParam: {token}})
+	var next string
+	expect(t, again, server.SessionToken, &next)
+	assert.NotEqual(t, token, next)
+}
+
+func TestAccountReconnect(t *testing.T) {
+	store, err := account.Open("")
+	require.NoError(t, err)
+	h := New()
+	h.Accounts = store
+	base := serve(t, h)
+	alice, aliceKey, err := store.Create("Alice")
+	require.NoError(t, err)
+	mallory, malloryKey, err := store.Create("Mallory")
+	require.NoError(t, err)
+
+	conn := dial(t, base, url.Values{gamechannel.AccountParam: {alice.ID.String()}, gamechannel.KeyParam: {aliceKey}})
+	var id uuid.UUID
+	expect(t, conn, server.IDSet, &id)
+	// they're given a name when they connect, then their account's
+	var name string
+	for name != "Alice" {
+		expect(t, conn, server.NameSet, &name)
+	}
+
+	// only the account that holds the seat can take it without a token
+	other := dial(t, base, url.Values{
+		gamechannel.PlayerParam:  {id.String()},
+		gamechannel.AccountParam: {mallory.ID.String()},
+		gamechannel.KeyParam:     {malloryKey},
+	})
+	rejected(t, other, "invalidToken")
+	wrongKey := dial(t, base, url.Values{
+		gamechannel.PlayerParam:  {id.String()},
+		gamechannel.AccountParam: {alice.ID.String()},
+		gamechannel.KeyParam:     {malloryKey},
+	})
+	rejected(t, wrongKey, "unauthorized")
+
+	device := dial(t, base, url.Values{
+		gamechannel.PlayerParam:  {id.String()},
+		gamechannel.AccountParam: {alice.ID.String()},
+		gamechannel.KeyParam:     {aliceKey},
+	})
+	var token string
+	expect(t, device, server.SessionToken, &token)
+	assert.NotEmpty(t, token)
+}
+
+func TestHistoryWithoutAccounts(t *testing.T) {
+	h := New()
+	h.History = &history.Memory{}
+	r := &game.Result{ID: uuid.New()}
+	h.recordGame(r)
+
+	got, ok := h.History.Result(r.ID)
+	assert.True(t, ok)
+	assert.Equal(t, r, got)
+}
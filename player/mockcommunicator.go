@@ -1,25 +1,56 @@
 package player
 
 import (
+	"net"
+	"sync"
+
 	"github.com/stretchr/testify/mock"
 )
 
 func NewMockCommunicator() *MockCommunicator {
-	return &MockCommunicator{}
+	return &MockCommunicator{closed: make(chan struct{})}
 }
 
 type MockCommunicator struct {
 	mock.Mock
+	mu      sync.Mutex
+	written [][]byte
+	closed  chan struct{}
+	once    sync.Once
 }
 
+// ReadMessage blocks until the communicator is closed, like a connection
+// whose client never says anything.
 func (mc *MockCommunicator) ReadMessage() (int, []byte, error) {
-	return 0, []byte{}, nil
+	<-mc.closed
+	return 0, nil, net.ErrClosed
 }
 
 func (mc *MockCommunicator) WriteMessage(messageType int, data []byte) error {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.written = append(mc.written, data)
 	return nil
 }
 
 func (mc *MockCommunicator) Close() error {
+	mc.once.Do(func() { close(mc.closed) })
 	return nil
 }
+
+// Closed reports whether Close has been called.
+func (mc *MockCommunicator) Closed() bool {
+	select {
+	case <-mc.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Written returns every message written to the communicator, in order.
+func (mc *MockCommunicator) Written() [][]byte {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return append([][]byte{}, mc.written...)
+}
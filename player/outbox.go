@@ -0,0 +1,150 @@
+package player
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// SendQueueSize is how many messages can be waiting to be written to a
+	// player before they are treated as a slow client.
+	SendQueueSize = 256
+	// WriteWait is how long a single write may take.
+	WriteWait = 10 * time.Second
+	// PongWait is how long a client may go without answering a ping.
+	PongWait = 60 * time.Second
+	// PingPeriod is how often clients are pinged. It must be less than PongWait.
+	PingPeriod = PongWait * 9 / 10
+)
+
+var ErrSendQueueFull = errors.New("player: send queue full")
+
+// A SlowClientPolicy says what happens when a player's send queue is full.
+type SlowClientPolicy int
+
+const (
+	// DisconnectSlowClient closes the player's socket. The client can
+	// reconnect, and is sent the current state of the game when it does.
+	DisconnectSlowClient SlowClientPolicy = iota
+	// DropMessages throws away messages until the queue has room again.
+	DropMessages
+)
+
+// A KeepaliveCommunicator is a Communicator that can be pinged, and have
+// deadlines set on it, as a *websocket.Conn can. Players on one are pinged
+// every PingPeriod and dropped if they don't answer.
+type KeepaliveCommunicator interface {
+	Communicator
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	SetPongHandler(h func(appData string) error)
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+}
+
+// An outgoing is an entry in a player's send queue: either a message, or a
+// socket to hang up once everything before it has been written.
+type outgoing struct {
+	message []byte
+	hangup  Communicator
+}
+
+// enqueue queues a message for the writer, applying the player's
+// SlowClientPolicy if there's no room.
+func (p *Player) enqueue(m []byte) error {
+	select {
+	case p.outbox <- outgoing{message: m}:
+		return nil
+	default:
+	}
+
+	switch p.SlowClientPolicy {
+	case DropMessages:
+		slog.Warn("send queue full, dropping message", "player", p)
+	default:
+		slog.Warn("send queue full, disconnecting player", "player", p)
+		p.currentSocket().Close()
+	}
+	return ErrSendQueueFull
+}
+
+// write sends queued messages to the player's socket, in order, and pings it,
+// until the player is closed.
+func (p *Player) write() {
+	ping := time.NewTicker(PingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case o := <-p.outbox:
+			p.writeOutgoing(o)
+		case <-ping.C:
+			p.ping()
+		case <-p.closing:
+			// flush what was sent before closing, ie the ack of a quit
+			for {
+				select {
+				case o := <-p.outbox:
+					p.writeOutgoing(o)
+				default:
+					p.currentSocket().Close()
+					return
+				}
+			}
+		}
+	}
+}
+
+// Hangup closes the player's current socket once the messages already queued
+// for it are written. Unlike Close, the player can still be given a new
+// socket with Reconnect.
+func (p *Player) Hangup() {
+	socket := p.currentSocket()
+	select {
+	case p.outbox <- outgoing{hangup: socket}:
+	default:
+		socket.Close()
+	}
+}
+
+func (p *Player) writeOutgoing(o outgoing) {
+	if o.hangup != nil {
+		o.hangup.Close()
+		return
+	}
+	p.writeMessage(o.message)
+}
+
+func (p *Player) writeMessage(m []byte) {
+	socket := p.currentSocket()
+	if ka, ok := socket.(KeepaliveCommunicator); ok {
+		ka.SetWriteDeadline(time.Now().Add(WriteWait))
+	}
+	if err := socket.WriteMessage(websocket.TextMessage, m); err != nil {
+		slog.Warn("error writing message", "player", p, "error", err)
+	}
+}
+
+func (p *Player) ping() {
+	ka, ok := p.currentSocket().(KeepaliveCommunicator)
+	if !ok {
+		return
+	}
+	if err := ka.WriteControl(websocket.PingMessage, nil, time.Now().Add(WriteWait)); err != nil {
+		slog.Warn("error pinging", "player", p, "error", err)
+	}
+}
+
+// keepalive makes reads from socket fail if the client stops answering pings.
+func keepalive(socket Communicator) {
+	ka, ok := socket.(KeepaliveCommunicator)
+	if !ok {
+		return
+	}
+	ka.SetReadDeadline(time.Now().Add(PongWait))
+	ka.SetPongHandler(func(string) error {
+		return ka.SetReadDeadline(time.Now().Add(PongWait))
+	})
+}
@@ -0,0 +1,141 @@
+package player
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A blockingCommunicator is a client that doesn't read anything until it is
+// released.
+type blockingCommunicator struct {
+	*MockCommunicator
+	release chan struct{}
+}
+
+func newBlockingCommunicator() *blockingCommunicator {
+	return &blockingCommunicator{MockCommunicator: NewMockCommunicator(), release: make(chan struct{})}
+}
+
+func (bc *blockingCommunicator) WriteMessage(messageType int, data []byte) error {
+	<-bc.release
+	return bc.MockCommunicator.WriteMessage(messageType, data)
+}
+
+// A keepaliveCommunicator records what keepalive set on it.
+type keepaliveCommunicator struct {
+	*MockCommunicator
+	readDeadline time.Time
+	pongHandler  func(string) error
+}
+
+func (kc *keepaliveCommunicator) SetReadDeadline(t time.Time) error {
+	kc.readDeadline = t
+	return nil
+}
+
+func (kc *keepaliveCommunicator) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+func (kc *keepaliveCommunicator) SetPongHandler(h func(string) error) {
+	kc.pongHandler = h
+}
+
+func (kc *keepaliveCommunicator) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	return nil
+}
+
+// fillQueue sends messages to p until one doesn't fit, returning how many did.
+func fillQueue(t *testing.T, p *Player) int {
+	for i := 0; i < SendQueueSize*2; i++ {
+		if err := p.Message(server.Awoo, i); err != nil {
+			assert.ErrorIs(t, err, ErrSendQueueFull)
+			return i
+		}
+	}
+	assert.FailNow(t, "send queue never filled")
+	return 0
+}
+
+func TestMessagesAreWrittenInOrder(t *testing.T) {
+	c := NewMockCommunicator()
+	p := NewPlayer(c)
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, p.Message(server.Awoo, i))
+	}
+
+	// idSet, nameSet and sessionToken are sent first
+	assert.Eventually(t, func() bool { return len(c.Written()) == 103 }, time.Second, time.Millisecond)
+	for i, raw := range c.Written()[3:] {
+		var m struct {
+			Payload int `json:"payload"`
+		}
+		assert.Nil(t, json.Unmarshal(raw, &m))
+		assert.Equal(t, i, m.Payload)
+	}
+}
+
+func TestSlowClientIsDisconnected(t *testing.T) {
+	c := newBlockingCommunicator()
+	defer close(c.release)
+	p := NewPlayer(c)
+
+	fillQueue(t, p)
+	assert.True(t, c.Closed())
+}
+
+func TestSlowClientMessagesAreDropped(t *testing.T) {
+	c := newBlockingCommunicator()
+	p := NewPlayer(c)
+	p.SlowClientPolicy = DropMessages
+
+	sent := fillQueue(t, p)
+	assert.False(t, c.Closed())
+
+	// everything that fit is still written
+	close(c.release)
+	assert.Eventually(t, func() bool { return len(c.Written()) == sent+3 }, time.Second, time.Millisecond)
+}
+
+func TestCloseFlushesQueue(t *testing.T) {
+	c := NewMockCommunicator()
+	p := NewPlayer(c)
+	p.Message(server.Awoo, nil)
+	p.Close()
+	p.Close()
+
+	assert.Eventually(t, c.Closed, time.Second, time.Millisecond)
+	assert.Len(t, c.Written(), 4)
+}
+
+func TestKeepalive(t *testing.T) {
+	c := &keepaliveCommunicator{MockCommunicator: NewMockCommunicator()}
+	keepalive(c)
+	assert.WithinDuration(t, time.Now().Add(PongWait), c.readDeadline, time.Second)
+
+	c.readDeadline = time.Time{}
+	assert.Nil(t, c.pongHandler(""))
+	assert.WithinDuration(t, time.Now().Add(PongWait), c.readDeadline, time.Second)
+}
+
+func TestHangup(t *testing.T) {
+	c := NewMockCommunicator()
+	p := NewPlayer(c)
+	p.Message(server.Awoo, nil)
+	p.Hangup()
+	assert.Eventually(t, c.Closed, time.Second, time.Millisecond)
+	assert.Len(t, c.Written(), 4)
+
+	// the player can still be given a new socket
+	again := NewMockCommunicator()
+	p.mu.Lock()
+	p.socket = again
+	p.mu.Unlock()
+	p.Message(server.Awoo, nil)
+	assert.Eventually(t, func() bool { return len(again.Written()) == 1 }, time.Second, time.Millisecond)
+}
@@ -1,8 +1,11 @@
 package player
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/awoo-detat/werewolf/gamechannel"
 	"github.com/awoo-detat/werewolf/gamechannel/client"
@@ -13,13 +16,29 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// A Player is shared between the game, which sets its name, role and views
+// while holding the game's lock, and the goroutines reading from and writing
+// to its socket. mu guards the fields those goroutines also touch.
+//
+// Addr is the host the player connected from, if known. AccountID is the
+// account they signed in with, or uuid.Nil if they didn't; kicking a player
+// bans their account.
 type Player struct {
-	ID          uuid.UUID  `json:"id"`
-	Name        string     `json:"name"`
-	Role        *role.Role `json:"-"`
-	Views       []*View    `json:"-"`
-	socket      Communicator
-	gameChannel gamechannel.GameChannel
+	ID               uuid.UUID        `json:"id"`
+	Name             string           `json:"name"`
+	Role             *role.Role       `json:"-"`
+	Views            []*View          `json:"-"`
+	SlowClientPolicy SlowClientPolicy `json:"-"`
+	Addr             string           `json:"-"`
+	AccountID        uuid.UUID        `json:"-"`
+	token            string
+	socket           Communicator
+	outbox           chan outgoing
+	closing          chan struct{}
+	closeOnce        sync.Once
+	gameChannel      gamechannel.GameChannel
+	gameDone         <-chan struct{}
+	mu               sync.RWMutex
 }
 
 func NewPlayer(socket Communicator) *Player {
@@ -28,26 +47,46 @@ func NewPlayer(socket Communicator) *Player {
 		slog.Error("error generating name", "error", err)
 	}
 	p := &Player{
-		ID:     uuid.New(),
-		Name:   name.String(),
-		Views:  []*View{},
-		socket: socket,
+		ID:      uuid.New(),
+		Name:    name.String(),
+		Views:   []*View{},
+		socket:  socket,
+		outbox:  make(chan outgoing, SendQueueSize),
+		closing: make(chan struct{}),
 	}
+	go p.write()
 	p.Message(server.IDSet, p.ID)
 	p.Message(server.NameSet, p.Name)
+	p.rotateToken()
 	return p
 }
 
 func (p *Player) String() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 	if len(p.Name) != 0 {
 		return p.Name
 	}
 	return p.ID.String()
 }
 
+func (p *Player) MarshalJSON() ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return json.Marshal(&struct {
+		ID   uuid.UUID `json:"id"`
+		Name string    `json:"name"`
+	}{
+		ID:   p.ID,
+		Name: p.Name,
+	})
+}
+
 func (p *Player) SetName(name string) {
+	p.mu.Lock()
 	p.Name = name
-	slog.Info("setting player name", "ID", p.ID, "Name", p.Name)
+	p.mu.Unlock()
+	slog.Info("setting player name", "ID", p.ID, "Name", name)
 }
 
 func (p *Player) SetRole(r *role.Role) {
@@ -56,8 +95,35 @@ func (p *Player) SetRole(r *role.Role) {
 	slog.Info("setting player role", "player", p, "role", r)
 }
 
-func (p *Player) SetGameChannel(gc gamechannel.GameChannel) {
+// SetGameChannel joins the player to a game. Activities are sent on gc until
+// done is closed, after which they are dropped.
+func (p *Player) SetGameChannel(gc gamechannel.GameChannel, done <-chan struct{}) {
 	p.gameChannel = gc
+	p.gameDone = done
+}
+
+// send passes an activity to the game, unless the game has stopped.
+func (p *Player) send(a *gamechannel.Activity) bool {
+	select {
+	case p.gameChannel <- a:
+		return true
+	case <-p.gameDone:
+		slog.Info("game has stopped, dropping activity", "player", p, "activity", a.Type)
+		return false
+	}
+}
+
+// Close writes any messages still queued for the player, then closes their
+// socket, which ends Play.
+func (p *Player) Close() error {
+	p.closeOnce.Do(func() { close(p.closing) })
+	return nil
+}
+
+// Reset forgets the player's role and views, ready for a new game.
+func (p *Player) Reset() {
+	p.Role = nil
+	p.Views = []*View{}
 }
 
 func (p *Player) AddView(v *View) {
@@ -70,34 +136,104 @@ func (p *Player) AddView(v *View) {
 
 func (p *Player) Reveal() *server.RevealedPlayer {
 	return &server.RevealedPlayer{
-		ID:   p.ID,
-		Name: p.Name,
-		Role: p.Role,
+		ID:     p.ID,
+		Name:   p.Name,
+		Role:   p.Role,
+		Tinker: p.Role != nil && p.Role.IsTinker(),
+		Lover:  p.Role != nil && p.Role.IsLover(),
 	}
 }
 
-// Message handles sending a message to the client, wrapping it in error handling
+// Message queues a message to the client. The payload is encoded straight
+// away, so it may be changed once Message returns.
 func (p *Player) Message(t server.MessageType, payload interface{}) error {
 	m, err := server.NewMessage(t, payload)
 	if err != nil {
 		return err
 	}
 	slog.Info("sending message to player", "message", m, "player", p)
-	return p.socket.WriteMessage(1, m)
+	return p.enqueue(m)
 }
 
-func (p *Player) Reconnect(c Communicator) {
-	slog.Info("player reconnecting", "player", p)
+func (p *Player) currentSocket() Communicator {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.socket
+}
+
+// SendError sends an error to the client. If the error was caused by a
+// request, requestID should reference it.
+func (p *Player) SendError(requestID string, err error) error {
+	return p.Message(server.Error, server.NewErrorMessage(requestID, err))
+}
+
+// Acknowledge tells the client that a versioned request has been accepted.
+// Legacy messages carry no request ID and are not acknowledged.
+func (p *Player) Acknowledge(m *client.Message) error {
+	if m.RequestID == "" {
+		return nil
+	}
+	return p.Message(server.Ack, &server.Acknowledgement{RequestID: m.RequestID, MessageType: string(m.Type)})
+}
+
+// Reconnect gives the player a new socket, if token is their session token.
+// The token is used up: a new one is sent on the new socket.
+func (p *Player) Reconnect(c Communicator, token string) error {
+	p.mu.Lock()
+	if !p.checkToken(token) {
+		p.mu.Unlock()
+		slog.Warn("reconnect with bad token", "player", p.ID)
+		return ErrInvalidToken
+	}
+	p.takeOver(c)
+	return nil
+}
+
+// Replace gives the player's seat to a new connection without a token, as
+// when a substitute takes over from someone who quit. The old token stops
+// working.
+func (p *Player) Replace(c Communicator) {
+	p.mu.Lock()
+	p.takeOver(c)
+}
+
+// takeOver swaps in a new socket and starts playing on it. The caller must
+// hold p.mu, which is released.
+func (p *Player) takeOver(c Communicator) {
+	old := p.socket
 	p.socket = c
+	// nobody else can use the old token once the socket is swapped
+	p.token = ""
+	p.mu.Unlock()
+	p.rotateToken()
+	slog.Info("player reconnecting", "player", p)
+
+	// the old connection may still be open, ie in another tab
+	old.Close()
 	go p.Play()
-	p.gameChannel <- &gamechannel.Activity{Type: gamechannel.Reconnect, From: p.ID}
+	p.send(&gamechannel.Activity{Type: gamechannel.Reconnect, From: p.ID})
 }
 
+// Play reads messages from the player's socket and passes them to the game,
+// until the socket is closed. If the player didn't quit or reconnect, the game
+// is told they have disconnected.
 func (p *Player) Play() {
-	defer p.socket.Close()
+	socket := p.currentSocket()
+	keepalive(socket)
+	quit := false
+	defer func() {
+		if quit {
+			p.Hangup()
+			return
+		}
+		socket.Close()
+		if p.currentSocket() == socket {
+			p.send(&gamechannel.Activity{Type: gamechannel.Disconnect, From: p.ID})
+		}
+	}()
 
 	for {
-		_, c, err := p.socket.ReadMessage()
+		_, c, err := socket.ReadMessage()
 		if err != nil {
 			// TODO!!
 			if websocket.IsCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
@@ -111,7 +247,12 @@ func (p *Player) Play() {
 		m, err := client.Decode(c)
 		if err != nil {
 			slog.Warn("player: error decoding", "error", err)
-			p.Message(server.Error, err)
+			var de *client.DecodeError
+			requestID := ""
+			if errors.As(err, &de) {
+				requestID = de.RequestID
+			}
+			p.SendError(requestID, err)
 			continue
 		}
 
@@ -119,26 +260,46 @@ func (p *Player) Play() {
 
 		switch m.Type {
 		case client.Awoo:
-			p.gameChannel <- &gamechannel.Activity{Type: gamechannel.Awoo, From: p.ID}
+			p.send(&gamechannel.Activity{Type: gamechannel.Awoo, From: p.ID, RequestID: m.RequestID})
 		case client.SetName:
-			p.SetName(m.PlayerName)
-			p.gameChannel <- &gamechannel.Activity{Type: gamechannel.SetName, From: p.ID, Value: p.Name}
+			// the game sets the name, so that it doesn't change underneath it
+			p.send(&gamechannel.Activity{Type: gamechannel.SetName, From: p.ID, Value: m.Payload.(*client.SetNamePayload).PlayerName, RequestID: m.RequestID})
 		case client.SetRoleset:
-			p.gameChannel <- &gamechannel.Activity{Type: gamechannel.SetRoleset, From: p.ID, Value: m.Roleset}
+			p.send(&gamechannel.Activity{Type: gamechannel.SetRoleset, From: p.ID, Value: m.Payload.(*client.SetRolesetPayload).Roleset, RequestID: m.RequestID})
 		case client.Vote:
-			p.gameChannel <- &gamechannel.Activity{Type: gamechannel.Vote, From: p.ID, Value: m.Target}
+			p.send(&gamechannel.Activity{Type: gamechannel.Vote, From: p.ID, Value: m.Payload.(*client.TargetPayload).Target, RequestID: m.RequestID})
 		case client.NightAction:
-			p.gameChannel <- &gamechannel.Activity{Type: gamechannel.NightAction, From: p.ID, Value: m.Target}
+			p.send(&gamechannel.Activity{Type: gamechannel.NightAction, From: p.ID, Value: m.Payload.(*client.TargetPayload).Target, RequestID: m.RequestID})
 		case client.Start:
-			p.gameChannel <- &gamechannel.Activity{Type: gamechannel.Start, From: p.ID}
+			p.send(&gamechannel.Activity{Type: gamechannel.Start, From: p.ID, RequestID: m.RequestID})
+		case client.ResetGame:
+			p.send(&gamechannel.Activity{Type: gamechannel.ResetGame, From: p.ID, RequestID: m.RequestID})
+		case client.TransferLeader:
+			p.send(&gamechannel.Activity{Type: gamechannel.TransferLeader, From: p.ID, Value: m.Payload.(*client.TargetPayload).Target, RequestID: m.RequestID})
+		case client.Kick:
+			p.send(&gamechannel.Activity{Type: gamechannel.Kick, From: p.ID, Value: m.Payload.(*client.TargetPayload).Target, RequestID: m.RequestID})
+		case client.SetRevealMode:
+			p.send(&gamechannel.Activity{Type: gamechannel.SetRevealMode, From: p.ID, Value: m.Payload.(*client.SetRevealModePayload).Mode, RequestID: m.RequestID})
+		case client.Shoot:
+			p.send(&gamechannel.Activity{Type: gamechannel.Shoot, From: p.ID, Value: m.Payload.(*client.TargetPayload).Target, RequestID: m.RequestID})
+		case client.ChooseLovers:
+			p.send(&gamechannel.Activity{Type: gamechannel.ChooseLovers, From: p.ID, Value: m.Payload, RequestID: m.RequestID})
+		case client.UsePotion:
+			p.send(&gamechannel.Activity{Type: gamechannel.UsePotion, From: p.ID, Value: m.Payload, RequestID: m.RequestID})
+		case client.SetLynchRule:
+			p.send(&gamechannel.Activity{Type: gamechannel.SetLynchRule, From: p.ID, Value: m.Payload, RequestID: m.RequestID})
 		case client.Quit:
 			slog.Info("player is quitting", "player", p)
-			p.gameChannel <- &gamechannel.Activity{Type: gamechannel.Quit, From: p.ID}
-			p.socket.Close()
-			break
+			// acknowledged first, since the game may close the player
+			p.Acknowledge(m)
+			p.send(&gamechannel.Activity{Type: gamechannel.Quit, From: p.ID, RequestID: m.RequestID})
+			quit = true
+			return
 		default:
-			p.Message(server.Error, fmt.Sprintf("unknown message %+v", m))
+			p.SendError(m.RequestID, fmt.Errorf("unknown message %+v", m))
 			slog.Warn("unknown message ", "message", m)
+			continue
 		}
+		p.Acknowledge(m)
 	}
 }
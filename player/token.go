@@ -0,0 +1,30 @@
+package player
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/secret"
+)
+
+// TokenBytes is how much randomness goes into a session token.
+const TokenBytes = 32
+
+var ErrInvalidToken = errors.New("player: invalid session token")
+
+// rotateToken issues the player a new session token and sends it only to
+// them. Anything sent after it goes to the same socket.
+func (p *Player) rotateToken() {
+	p.mu.Lock()
+	p.token = secret.New(TokenBytes)
+	token := p.token
+	p.mu.Unlock()
+	p.Message(server.SessionToken, token)
+}
+
+// checkToken reports whether token is the player's current session token.
+// The caller must hold p.mu, so that a token can only be used once.
+func (p *Player) checkToken(token string) bool {
+	return p.token != "" && subtle.ConstantTimeCompare([]byte(p.token), []byte(token)) == 1
+}
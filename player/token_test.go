@@ -0,0 +1,48 @@
+package player
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lastToken returns the last session token written to c.
+func lastToken(c *MockCommunicator) string {
+	token := ""
+	for _, raw := range c.Written() {
+		var m struct {
+			Type    string `json:"messageType"`
+			Payload string `json:"payload"`
+		}
+		if json.Unmarshal(raw, &m) == nil && m.Type == "sessionToken" {
+			token = m.Payload
+		}
+	}
+	return token
+}
+
+func TestReconnectToken(t *testing.T) {
+	assert := assert.New(t)
+	c := NewMockCommunicator()
+	p := NewPlayer(c)
+	// no game is listening, so reconnects aren't passed on
+	done := make(chan struct{})
+	close(done)
+	p.SetGameChannel(make(gamechannel.GameChannel), done)
+	assert.Eventually(func() bool { return lastToken(c) != "" }, time.Second, time.Millisecond)
+	token := lastToken(c)
+
+	b, err := json.Marshal(p)
+	assert.Nil(err)
+	assert.False(strings.Contains(string(b), token), "the token is never broadcast")
+
+	assert.ErrorIs(p.Reconnect(NewMockCommunicator(), "nope"), ErrInvalidToken)
+	assert.ErrorIs(p.Reconnect(NewMockCommunicator(), ""), ErrInvalidToken)
+	assert.Nil(p.Reconnect(NewMockCommunicator(), token))
+	assert.ErrorIs(p.Reconnect(NewMockCommunicator(), token), ErrInvalidToken, "tokens only work once")
+}
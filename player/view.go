@@ -11,10 +11,14 @@ import (
 // of the game). It may not be successful, as in the case of
 // a seer viewing a non-max, and it may even be incorrect,
 // if there are tinkers!
+//
+// A team view only tells which team a player was on, for games that don't
+// reveal roles on death.
 type View struct {
 	Player    *Player
 	Attribute role.Attribute
 	Role      *role.Role
+	Team      *role.PlayerType `json:",omitempty"`
 	Hit       bool
 	GamePhase int
 }
@@ -37,9 +41,21 @@ func NewRoleView(p *Player, r *role.Role, phase int) *View {
 	}
 }
 
+func NewTeamView(p *Player, team role.PlayerType, phase int) *View {
+	return &View{
+		Player:    p,
+		Team:      &team,
+		Hit:       true,
+		GamePhase: phase,
+	}
+}
+
 func (v *View) For() string {
 	if v.Role != nil {
 		return v.Role.Name
 	}
+	if v.Team != nil {
+		return v.Team.String()
+	}
 	return v.Attribute.String()
 }
@@ -23,3 +23,11 @@ func TestRoleView(t *testing.T) {
 
 	assert.Equal(t, r.Name, v.For())
 }
+
+func TestTeamView(t *testing.T) {
+	p := NewPlayer(NewMockCommunicator())
+	v := NewTeamView(p, role.Evil, 0)
+
+	assert.Equal(t, "Evil", v.For())
+	assert.Nil(t, v.Role)
+}
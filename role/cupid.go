@@ -0,0 +1,14 @@
+package role
+
+func Cupid() *Role {
+	return &Role{
+		Name:           "Cupid",
+		Description:    "On the first night, choose two players to fall in love. If one dies, so does the other.",
+		Team:           Good,
+		VoteMultiplier: 1,
+		Health:         1,
+		Parity:         1,
+		Alive:          true,
+		Actions:        chooseLovers,
+	}
+}
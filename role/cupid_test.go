@@ -0,0 +1,20 @@
+package role
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCupid(t *testing.T) {
+	cupid := Cupid()
+	assert.True(t, cupid.ChoosesLovers())
+	assert.False(t, cupid.IsLover())
+	assert.False(t, Villager().ChoosesLovers())
+
+	// a lover is still seen for what they are
+	wolf := Werewolf()
+	wolf.SetLover()
+	assert.True(t, wolf.IsLover())
+	assert.True(t, wolf.ViewForMaxEvil())
+}
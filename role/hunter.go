@@ -11,3 +11,18 @@ func Hunter() *Role {
 		Alive:          true,
 	}
 }
+
+// RevengeHunter is the classic Hunter: rather than holding off evil while
+// alive, they shoot someone when they die.
+func RevengeHunter() *Role {
+	return &Role{
+		Name:           "Revenge Hunter",
+		Description:    "If you go down, you're taking someone with you. Choose wisely.",
+		Team:           Good,
+		VoteMultiplier: 1,
+		Health:         1,
+		Parity:         1,
+		Alive:          true,
+		Actions:        revengeShot,
+	}
+}
@@ -49,3 +49,12 @@ func (suite *HunterTestSuite) TestKill() {
 func TestHunterTestSuite(t *testing.T) {
 	suite.Run(t, new(HunterTestSuite))
 }
+
+func TestRevengeHunter(t *testing.T) {
+	hunter := RevengeHunter()
+	assert.Equal(t, Good, hunter.Team)
+	assert.Equal(t, 1, hunter.Parity)
+	assert.True(t, hunter.HasRevengeShot())
+	assert.False(t, Hunter().HasRevengeShot())
+	assert.False(t, hunter.CanNightKill())
+}
@@ -0,0 +1,14 @@
+package role
+
+func Jester() *Role {
+	return &Role{
+		Name:           "Jester",
+		Description:    "You win if the village lynches you. Be suspicious, but not too suspicious.",
+		Team:           Neutral,
+		VoteMultiplier: 1,
+		Health:         1,
+		Parity:         1,
+		Alive:          true,
+		WinCondition:   WinIfLynched,
+	}
+}
@@ -0,0 +1,16 @@
+package role
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJester(t *testing.T) {
+	jester := Jester()
+	assert.Equal(t, PlayerType(Neutral), jester.Team)
+	assert.Equal(t, WinIfLynched, jester.WinCondition)
+	assert.False(t, jester.IsMaxEvil())
+	assert.False(t, jester.CanNightKill())
+	assert.False(t, jester.CanSoloKill())
+}
@@ -1,6 +1,7 @@
 package role
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 )
@@ -29,6 +30,20 @@ func (p PlayerType) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("\"%s\"", p)), nil
 }
 
+func (p *PlayerType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	for _, t := range []PlayerType{Good, Evil, Neutral} {
+		if t.String() == s {
+			*p = t
+			return nil
+		}
+	}
+	return fmt.Errorf("role: unknown team %q", s)
+}
+
 type Attribute int
 
 const (
@@ -36,6 +51,7 @@ const (
 	AuxEvilAttribute
 	SeerAttribute
 	TinkerAttribute
+	LoverAttribute
 )
 
 func (a Attribute) String() string {
@@ -48,6 +64,8 @@ func (a Attribute) String() string {
 		return "Seer"
 	case TinkerAttribute:
 		return "Tinker"
+	case LoverAttribute:
+		return "Lover"
 	}
 	return ""
 }
@@ -56,6 +74,36 @@ func (a Attribute) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("\"%s\"", a)), nil
 }
 
+func (a *Attribute) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*a = 0
+		return nil
+	}
+	for _, attr := range []Attribute{MaxEvilAttribute, AuxEvilAttribute, SeerAttribute, TinkerAttribute, LoverAttribute} {
+		if attr.String() == s {
+			*a = attr
+			return nil
+		}
+	}
+	return fmt.Errorf("role: unknown attribute %q", s)
+}
+
+// A WinCondition says how a role wins. Most roles win with their team;
+// neutral roles win on their own terms.
+type WinCondition int
+
+const (
+	WinWithTeam WinCondition = iota
+	// WinIfLynched wins if the player is lynched, whoever goes on to win.
+	WinIfLynched
+	// WinAlone wins by outlasting everyone, or all but one.
+	WinAlone
+)
+
 type Action int
 
 const (
@@ -65,18 +113,25 @@ const (
 	viewForAux
 	randomN0Clear
 	knowsMaxes
+	revengeShot
+	soloKill
+	chooseLovers
+	roleblock
+	savePotion
+	poisonPotion
 )
 
 type Role struct {
-	Name           string     `json:"name"`
-	Description    string     `json:"description"`
-	Team           PlayerType `json:"team"`
-	Parity         int        `json:"-"`
-	VoteMultiplier int        `json:"-"`
-	Health         int        `json:"-"`
-	Alive          bool       `json:"alive"`
-	Actions        Action     `json:"night_action"`
-	Attributes     Attribute  `json:"-"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	Team           PlayerType   `json:"team"`
+	Parity         int          `json:"-"`
+	VoteMultiplier int          `json:"-"`
+	Health         int          `json:"-"`
+	Alive          bool         `json:"alive"`
+	Actions        Action       `json:"night_action"`
+	Attributes     Attribute    `json:"-"`
+	WinCondition   WinCondition `json:"-"`
 }
 
 func (r *Role) String() string {
@@ -151,6 +206,58 @@ func (r *Role) KnowsMaxes() bool {
 	return r.Actions&knowsMaxes > 0
 }
 
+// IsTinker returns whether the player's views are inverted. Nobody is told
+// they are a Tinker.
+func (r *Role) IsTinker() bool {
+	return r.Attributes&TinkerAttribute > 0
+}
+
+// CanSoloKill returns whether the player kills at night on their own,
+// separately from the wolves.
+func (r *Role) CanSoloKill() bool {
+	return r.Actions&soloKill > 0
+}
+
+// ChoosesLovers returns whether the player links two lovers on night 0.
+func (r *Role) ChoosesLovers() bool {
+	return r.Actions&chooseLovers > 0
+}
+
+// IsLover returns whether the player is linked to another, so that each dies
+// with the other.
+func (r *Role) IsLover() bool {
+	return r.Attributes&LoverAttribute > 0
+}
+
+// SetLover links the player to another; the game keeps track of who.
+func (r *Role) SetLover() {
+	r.Attributes = r.Attributes | LoverAttribute
+}
+
+// CanRoleblock returns whether the player chooses someone each night whose
+// night action is cancelled.
+func (r *Role) CanRoleblock() bool {
+	return r.Actions&roleblock > 0
+}
+
+// HasSavePotion returns whether the player starts with a potion that saves
+// someone from dying at night. The game keeps track of whether it is used.
+func (r *Role) HasSavePotion() bool {
+	return r.Actions&savePotion > 0
+}
+
+// HasPoisonPotion returns whether the player starts with a potion that kills
+// someone at night. The game keeps track of whether it is used.
+func (r *Role) HasPoisonPotion() bool {
+	return r.Actions&poisonPotion > 0
+}
+
+// HasRevengeShot returns whether the player takes someone down with them when
+// they die.
+func (r *Role) HasRevengeShot() bool {
+	return r.Actions&revengeShot > 0
+}
+
 // SetTinker makes a role a Tinker: all views will be the inverse of the truth
 func (r *Role) SetTinker() {
 	r.Attributes = r.Attributes | TinkerAttribute
@@ -0,0 +1,37 @@
+package role
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	r := Sorcerer()
+
+	b, err := json.Marshal(r)
+	assert.Nil(err)
+	decoded := &Role{}
+	assert.Nil(json.Unmarshal(b, decoded))
+
+	assert.Equal(r.Name, decoded.Name)
+	assert.Equal(PlayerType(Evil), decoded.Team)
+	assert.Equal(r.Actions, decoded.Actions)
+}
+
+func TestAttributeJSON(t *testing.T) {
+	for _, a := range []Attribute{MaxEvilAttribute, AuxEvilAttribute, SeerAttribute, TinkerAttribute, 0} {
+		b, err := json.Marshal(a)
+		assert.Nil(t, err)
+		var decoded Attribute
+		assert.Nil(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, a, decoded)
+	}
+
+	var a Attribute
+	assert.Error(t, json.Unmarshal([]byte(`"Sparkly"`), &a))
+	var p PlayerType
+	assert.Error(t, json.Unmarshal([]byte(`"Chaotic"`), &p))
+}
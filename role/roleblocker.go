@@ -0,0 +1,15 @@
+package role
+
+func Roleblocker() *Role {
+	return &Role{
+		Name:           "Roleblocker",
+		Description:    "You don't know the wolf, but are evil. Each night you choose a player, and whatever they do that night doesn't happen.",
+		Team:           Evil,
+		VoteMultiplier: 1,
+		Health:         1,
+		Parity:         1,
+		Alive:          true,
+		Actions:        roleblock,
+		Attributes:     AuxEvilAttribute,
+	}
+}
@@ -0,0 +1,16 @@
+package role
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleblocker(t *testing.T) {
+	roleblocker := Roleblocker()
+	assert.Equal(t, PlayerType(Evil), roleblocker.Team)
+	assert.True(t, roleblocker.CanRoleblock())
+	assert.True(t, roleblocker.ViewForAuxEvil())
+	assert.False(t, roleblocker.ViewForMaxEvil())
+	assert.False(t, roleblocker.CanNightKill())
+}
@@ -0,0 +1,28 @@
+package roleset
+
+import (
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/win"
+)
+
+func ChaosEight() *Roleset {
+	return &Roleset{
+		Name:        "Chaos Eight",
+		Description: "A wolf, a seer, and two wild cards: a Jester who wants to hang and a Serial Killer out for everyone.",
+		Roles: []*role.Role{
+			role.Werewolf(),
+			role.Cultist(),
+			role.Seer(),
+			role.Jester(),
+			role.SerialKiller(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+		},
+		Win: win.Loners{Then: win.Parity{}},
+	}
+}
+
+func init() {
+	registerRoleset(ChaosEight())
+}
@@ -0,0 +1,27 @@
+package roleset
+
+import (
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/win"
+)
+
+func CleansingSeven() *Roleset {
+	return &Roleset{
+		Name:        "Cleansing Seven",
+		Description: "One wolf, one cultist, and the village doesn't win until both are dead.",
+		Roles: []*role.Role{
+			role.Werewolf(),
+			role.Cultist(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+		},
+		Win: win.AllEvilDead{},
+	}
+}
+
+func init() {
+	registerRoleset(CleansingSeven())
+}
@@ -0,0 +1,28 @@
+package roleset
+
+import (
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/win"
+)
+
+func CupidsEight() *Roleset {
+	return &Roleset{
+		Name:        "Cupid's Eight",
+		Description: "One wolf, one cultist, a seer, and a Cupid who links two players on the first night.",
+		Roles: []*role.Role{
+			role.Werewolf(),
+			role.Cultist(),
+			role.Seer(),
+			role.Cupid(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+		},
+		Win: win.Lovers{Then: win.Parity{}},
+	}
+}
+
+func init() {
+	registerRoleset(CupidsEight())
+}
@@ -0,0 +1,27 @@
+package roleset
+
+import (
+	"github.com/awoo-detat/werewolf/role"
+)
+
+func HexedNiner() *Roleset {
+	return &Roleset{
+		Name:        "Hexed Niner",
+		Description: "Two wolves and a Roleblocker who can stop the seer, against a Witch with one potion to save and one to kill.",
+		Roles: []*role.Role{
+			role.Werewolf(),
+			role.Werewolf(),
+			role.Roleblocker(),
+			role.Seer(),
+			role.Witch(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+		},
+	}
+}
+
+func init() {
+	registerRoleset(HexedNiner())
+}
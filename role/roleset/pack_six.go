@@ -0,0 +1,26 @@
+package roleset
+
+import (
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/win"
+)
+
+func PackSix() *Roleset {
+	return &Roleset{
+		Name:        "Pack Six",
+		Description: "Two wolves, and they don't win until they outnumber the village.",
+		Roles: []*role.Role{
+			role.Werewolf(),
+			role.Werewolf(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+		},
+		Win: win.Majority{},
+	}
+}
+
+func init() {
+	registerRoleset(PackSix())
+}
@@ -0,0 +1,27 @@
+package roleset
+
+import (
+	"github.com/awoo-detat/werewolf/role"
+)
+
+func RevengeNiner() *Roleset {
+	return &Roleset{
+		Name:        "Revenge Niner",
+		Description: "A Basic Niner, but the Hunter shoots someone when they die.",
+		Roles: []*role.Role{
+			role.Werewolf(),
+			role.Werewolf(),
+			role.Cultist(),
+			role.RevengeHunter(),
+			role.Seer(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+			role.Villager(),
+		},
+	}
+}
+
+func init() {
+	registerRoleset(RevengeNiner())
+}
@@ -2,18 +2,40 @@ package roleset
 
 import (
 	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/win"
 )
 
+// A Roleset is the roles dealt out for a game. TinkerSlots are indexes into
+// Roles that are always Tinkers, and RandomTinkers picks more each game; see
+// ApplyTinkers. Win decides when the game is over; if it is nil, win.Parity
+// is used. Rolesets with roles that play alone, or with lovers, wrap theirs
+// in win.Loners or win.Lovers. If Lovers is set and there is no Cupid to
+// choose them, two players are linked at random when the game starts.
 type Roleset struct {
-	Name        string       `json:"name"`
-	Description string       `json:"description"`
-	Roles       []*role.Role `json:"roles"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Roles         []*role.Role  `json:"roles"`
+	TinkerSlots   []int         `json:"tinkerSlots,omitempty"`
+	RandomTinkers []TinkerRule  `json:"randomTinkers,omitempty"`
+	Win           win.Condition `json:"-"`
+	Lovers        bool          `json:"lovers,omitempty"`
 }
 
 func (rs *Roleset) String() string {
 	return rs.Name
 }
 
+// Clone returns a copy of the roleset with its own copies of every role.
+func (rs *Roleset) Clone() *Roleset {
+	c := *rs
+	c.Roles = make([]*role.Role, len(rs.Roles))
+	for i, r := range rs.Roles {
+		copied := *r
+		c.Roles[i] = &copied
+	}
+	return &c
+}
+
 func List() map[string]*Roleset {
 	return sets
 }
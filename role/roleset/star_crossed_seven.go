@@ -0,0 +1,18 @@
+package roleset
+
+import (
+	"github.com/awoo-detat/werewolf/win"
+)
+
+func StarCrossedSeven() *Roleset {
+	rs := BasicSeven()
+	rs.Name = "Star-Crossed Seven"
+	rs.Description = "A Basic Seven, but two random players are lovers: if one dies, so does the other."
+	rs.Lovers = true
+	rs.Win = win.Lovers{Then: win.Parity{}}
+	return rs
+}
+
+func init() {
+	registerRoleset(StarCrossedSeven())
+}
@@ -0,0 +1,41 @@
+package roleset
+
+import (
+	"log/slog"
+	"math/rand"
+
+	"github.com/awoo-detat/werewolf/role"
+)
+
+// A TinkerRule makes Count randomly chosen roles on Team Tinkers.
+type TinkerRule struct {
+	Team  role.PlayerType `json:"team"`
+	Count int             `json:"count"`
+}
+
+// ApplyTinkers makes the roleset's Tinkers: the roles at TinkerSlots, then
+// those picked by RandomTinkers. The roles are changed in place, so it should
+// only be called on a roleset's own Clone.
+func (rs *Roleset) ApplyTinkers() {
+	for _, slot := range rs.TinkerSlots {
+		if slot < 0 || slot >= len(rs.Roles) {
+			slog.Warn("tinker slot out of range", "roleset", rs, "slot", slot)
+			continue
+		}
+		rs.Roles[slot].SetTinker()
+	}
+	for _, rule := range rs.RandomTinkers {
+		candidates := []*role.Role{}
+		for _, r := range rs.Roles {
+			if r.Team == rule.Team && !r.IsTinker() {
+				candidates = append(candidates, r)
+			}
+		}
+		for i, n := range rand.Perm(len(candidates)) {
+			if i == rule.Count {
+				break
+			}
+			candidates[n].SetTinker()
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package roleset
+
+import (
+	"testing"
+
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tinkers(rs *Roleset) []*role.Role {
+	found := []*role.Role{}
+	for _, r := range rs.Roles {
+		if r.IsTinker() {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+func TestTinkerSlots(t *testing.T) {
+	rs := Fiver().Clone()
+	rs.TinkerSlots = []int{0, 3, 99}
+	rs.ApplyTinkers()
+
+	assert.Equal(t, []*role.Role{rs.Roles[0], rs.Roles[3]}, tinkers(rs))
+}
+
+func TestRandomTinkers(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		rs := Niner().Clone()
+		rs.RandomTinkers = []TinkerRule{{Team: role.Good, Count: 2}, {Team: role.Evil, Count: 5}}
+		rs.ApplyTinkers()
+
+		good, evil := 0, 0
+		for _, r := range tinkers(rs) {
+			if r.Team == role.Good {
+				good++
+			} else {
+				evil++
+			}
+		}
+		assert.Equal(t, 2, good)
+		assert.Equal(t, 3, evil, "there are only three evil roles to pick")
+	}
+}
+
+func TestTinkeredNiner(t *testing.T) {
+	rs := List()["Tinkered Niner"].Clone()
+	rs.ApplyTinkers()
+	assert.Len(t, tinkers(rs), 1)
+	assert.Equal(t, role.Good, tinkers(rs)[0].Team)
+
+	// the registered roleset is untouched
+	assert.Empty(t, tinkers(List()["Tinkered Niner"]))
+}
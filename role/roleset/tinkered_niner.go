@@ -0,0 +1,17 @@
+package roleset
+
+import (
+	"github.com/awoo-detat/werewolf/role"
+)
+
+func TinkeredNiner() *Roleset {
+	rs := Niner()
+	rs.Name = "Tinkered Niner"
+	rs.Description = "A Basic Niner, but one good role is a Tinker: the seer sees them backwards, and they don't know it."
+	rs.RandomTinkers = []TinkerRule{{Team: role.Good, Count: 1}}
+	return rs
+}
+
+func init() {
+	registerRoleset(TinkeredNiner())
+}
@@ -0,0 +1,15 @@
+package role
+
+func SerialKiller() *Role {
+	return &Role{
+		Name:           "Serial Killer",
+		Description:    "You're on nobody's side. Kill someone every night, and be the last one standing.",
+		Team:           Neutral,
+		VoteMultiplier: 1,
+		Health:         1,
+		Parity:         1,
+		Alive:          true,
+		Actions:        soloKill,
+		WinCondition:   WinAlone,
+	}
+}
@@ -0,0 +1,18 @@
+package role
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerialKiller(t *testing.T) {
+	sk := SerialKiller()
+	assert.Equal(t, PlayerType(Neutral), sk.Team)
+	assert.Equal(t, WinAlone, sk.WinCondition)
+	assert.True(t, sk.CanSoloKill())
+	assert.False(t, sk.CanNightKill(), "they don't kill with the wolves")
+	assert.False(t, sk.IsMaxEvil())
+	assert.False(t, Werewolf().CanSoloKill())
+	assert.Equal(t, WinWithTeam, Werewolf().WinCondition)
+}
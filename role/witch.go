@@ -0,0 +1,14 @@
+package role
+
+func Witch() *Role {
+	return &Role{
+		Name:           "Witch",
+		Description:    "You have two potions, each good for one night: one saves a player from dying that night, the other kills them.",
+		Team:           Good,
+		VoteMultiplier: 1,
+		Health:         1,
+		Parity:         1,
+		Alive:          true,
+		Actions:        savePotion | poisonPotion,
+	}
+}
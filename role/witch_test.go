@@ -0,0 +1,17 @@
+package role
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWitch(t *testing.T) {
+	witch := Witch()
+	assert.Equal(t, PlayerType(Good), witch.Team)
+	assert.True(t, witch.HasSavePotion())
+	assert.True(t, witch.HasPoisonPotion())
+	assert.False(t, witch.CanRoleblock())
+	assert.False(t, Villager().HasSavePotion())
+	assert.False(t, Villager().HasPoisonPotion())
+}
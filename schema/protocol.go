@@ -0,0 +1,139 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/awoo-detat/werewolf/gamechannel/client"
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/role/roleset"
+	"github.com/awoo-detat/werewolf/tally"
+
+	"github.com/google/uuid"
+)
+
+// ClientPayloads maps every client message type to the type of its payload,
+// or nil if it has none.
+var ClientPayloads = map[client.MessageType]interface{}{
+	client.Awoo:           nil,
+	client.SetName:        client.SetNamePayload{},
+	client.SetRoleset:     client.SetRolesetPayload{},
+	client.Vote:           client.TargetPayload{},
+	client.NightAction:    client.TargetPayload{},
+	client.Start:          nil,
+	client.Quit:           nil,
+	client.ResetGame:      nil,
+	client.TransferLeader: client.TargetPayload{},
+	client.Kick:           client.TargetPayload{},
+	client.SetLynchRule:   client.SetLynchRulePayload{},
+	client.Shoot:          client.TargetPayload{},
+	client.SetRevealMode:  client.SetRevealModePayload{},
+	client.ChooseLovers:   client.LoversPayload{},
+	client.UsePotion:      client.PotionPayload{},
+}
+
+// ServerPayloads maps every server message type to the type of its payload,
+// or nil if it is always null. Pointers are used for payloads that may be null.
+var ServerPayloads = map[server.MessageType]interface{}{
+	server.Awoo:              "",
+	server.IDSet:             uuid.UUID{},
+	server.NameSet:           "",
+	server.PlayerJoin:        player.Player{},
+	server.PlayerLeave:       player.Player{},
+	server.AlivePlayerList:   []*player.Player{},
+	server.RolesetList:       roleset.RolesetMap{},
+	server.RolesetSelected:   roleset.Roleset{},
+	server.LeaderSet:         &player.Player{},
+	server.Password:          "",
+	server.TallyChanged:      tally.Tally{},
+	server.RoleAssigned:      role.Role{},
+	server.PhaseChanged:      server.Phase{},
+	server.View:              player.View{},
+	server.PlayerKilled:      nil,
+	server.GameOver:          server.GameOverMessage{},
+	server.Error:             server.ErrorMessage{},
+	server.Ack:               server.Acknowledgement{},
+	server.GameReset:         nil,
+	server.Kicked:            nil,
+	server.PlayerSubstituted: player.Player{},
+	server.SessionToken:      "",
+	server.Timeline:          []server.TimelineEvent{},
+	server.VoteHistory:       []tally.Day{},
+	server.LynchRuleSet:      server.LynchRule{},
+	server.RevengeShot:       server.RevengeShotPrompt{},
+	server.RevealModeSet:     "",
+}
+
+// Generate builds the schema of the whole protocol. Every message is in
+// $defs as "client.<messageType>" or "server.<messageType>", along with the
+// types they carry, and the root schema matches any one message.
+func Generate() *Schema {
+	g := newGenerator()
+	root := &Schema{
+		Schema: Draft,
+		Title:  "Werewolf websocket protocol",
+		OneOf:  []*Schema{},
+	}
+
+	clientTypes := []string{}
+	for t := range ClientPayloads {
+		clientTypes = append(clientTypes, string(t))
+	}
+	sort.Strings(clientTypes)
+	for _, t := range clientTypes {
+		payload := g.schemaFor(typeOf(ClientPayloads[client.MessageType(t)]))
+		g.defs["client."+t] = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"version":     {Const: client.ProtocolVersion},
+				"requestId":   {Type: "string"},
+				"messageType": {Const: t},
+				"payload":     payload,
+			},
+			Required:             []string{"version", "messageType"},
+			AdditionalProperties: false,
+		}
+		root.OneOf = append(root.OneOf, &Schema{Ref: "#/$defs/client." + t})
+	}
+
+	serverTypes := []string{}
+	for t := range ServerPayloads {
+		serverTypes = append(serverTypes, string(t))
+	}
+	sort.Strings(serverTypes)
+	for _, t := range serverTypes {
+		payload := g.schemaFor(typeOf(ServerPayloads[server.MessageType(t)]))
+		g.defs["server."+t] = &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"messageType": {Const: t},
+				"payload":     payload,
+			},
+			Required:             []string{"messageType", "payload"},
+			AdditionalProperties: false,
+		}
+		root.OneOf = append(root.OneOf, &Schema{Ref: "#/$defs/server." + t})
+	}
+
+	root.Defs = g.defs
+	return root
+}
+
+// JSON returns the indented encoding of the protocol schema, as checked in.
+func JSON() ([]byte, error) {
+	b, err := json.MarshalIndent(Generate(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func typeOf(v interface{}) reflect.Type {
+	if v == nil {
+		return nil
+	}
+	return reflect.TypeOf(v)
+}
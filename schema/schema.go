@@ -0,0 +1,130 @@
+// Package schema describes the websocket protocol as a JSON Schema, generated
+// by reflecting over the Go types that are sent over the wire.
+package schema
+
+//go:generate go test -run TestSchemaIsUpToDate -update
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
+)
+
+// Draft is the JSON Schema dialect of the generated schema.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// A Schema is the subset of JSON Schema needed to describe the protocol.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// overrides describes types whose JSON shape comes from a custom MarshalJSON
+// (or the standard library) rather than from their fields.
+var overrides = map[reflect.Type]*Schema{
+	reflect.TypeOf(uuid.UUID{}):        {Type: "string", Format: "uuid"},
+	reflect.TypeOf(time.Time{}):        {Type: "string", Format: "date-time"},
+	reflect.TypeOf(role.PlayerType(0)): {Type: "string", Enum: []string{role.Good.String(), role.PlayerType(role.Evil).String(), role.PlayerType(role.Neutral).String()}},
+	reflect.TypeOf(role.Attribute(0)):  {Type: "string", Enum: []string{role.MaxEvilAttribute.String(), role.AuxEvilAttribute.String(), role.SeerAttribute.String(), role.TinkerAttribute.String(), role.LoverAttribute.String(), ""}},
+}
+
+// A generator builds schemas for Go types, collecting named struct types
+// into defs so that each is only described once.
+type generator struct {
+	defs map[string]*Schema
+}
+
+func newGenerator() *generator {
+	return &generator{defs: make(map[string]*Schema)}
+}
+
+// defName is the key a named type is stored under in $defs, ie "player.Player".
+func defName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	return pkg[strings.LastIndex(pkg, "/")+1:] + "." + t.Name()
+}
+
+// schemaFor returns the schema of the JSON encoding of values of type t. A
+// nil type, used for messages without a payload, is described as null, as
+// are nil pointers.
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{Type: "null"}
+	}
+	if t.Kind() == reflect.Pointer {
+		return &Schema{OneOf: []*Schema{g.schemaFor(t.Elem()), {Type: "null"}}}
+	}
+	if s, ok := overrides[t]; ok {
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		name := defName(t)
+		if _, ok := g.defs[name]; !ok {
+			// reserve the name first in case the type refers to itself
+			g.defs[name] = &Schema{}
+			*g.defs[name] = *g.structSchema(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	}
+	panic(fmt.Sprintf("schema: cannot describe %s", t))
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		Required:             []string{},
+		AdditionalProperties: false,
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schemaFor(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
@@ -0,0 +1,300 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/gamechannel/client"
+	"github.com/awoo-detat/werewolf/gamechannel/server"
+	"github.com/awoo-detat/werewolf/player"
+	"github.com/awoo-detat/werewolf/role"
+	"github.com/awoo-detat/werewolf/role/roleset"
+	"github.com/awoo-detat/werewolf/tally"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const schemaFile = "protocol.schema.json"
+
+var update = flag.Bool("update", false, "rewrite "+schemaFile+" from the Go types")
+
+func TestSchemaIsUpToDate(t *testing.T) {
+	generated, err := JSON()
+	assert.Nil(t, err)
+
+	if *update {
+		assert.Nil(t, os.WriteFile(schemaFile, generated, 0644))
+		return
+	}
+
+	checkedIn, err := os.ReadFile(schemaFile)
+	assert.Nil(t, err)
+	assert.Equal(t, string(checkedIn), string(generated), "the protocol has changed; run go generate ./schema")
+}
+
+// messageTypes finds the values of every MessageType constant in a package
+// directory, so that new message types can't be forgotten.
+func messageTypes(t *testing.T, dir string) []string {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, 0)
+	assert.Nil(t, err)
+
+	types := []string{}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.CONST || len(gd.Specs) == 0 {
+					continue
+				}
+				first := gd.Specs[0].(*ast.ValueSpec)
+				if ident, ok := first.Type.(*ast.Ident); !ok || ident.Name != "MessageType" {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					lit := spec.(*ast.ValueSpec).Values[0].(*ast.BasicLit)
+					value, err := strconv.Unquote(lit.Value)
+					assert.Nil(t, err)
+					types = append(types, value)
+				}
+			}
+		}
+	}
+	return types
+}
+
+func TestEveryMessageTypeIsDescribed(t *testing.T) {
+	clientTypes := messageTypes(t, "../gamechannel/client")
+	assert.Len(t, ClientPayloads, len(clientTypes))
+	for _, mt := range clientTypes {
+		assert.Contains(t, ClientPayloads, client.MessageType(mt))
+	}
+
+	serverTypes := messageTypes(t, "../gamechannel/server")
+	assert.Len(t, ServerPayloads, len(serverTypes))
+	for _, mt := range serverTypes {
+		assert.Contains(t, ServerPayloads, server.MessageType(mt))
+	}
+}
+
+// validate checks a decoded JSON value against a schema, resolving
+// references against defs.
+func validate(defs map[string]*Schema, s *Schema, v interface{}) error {
+	if s.Ref != "" {
+		name := s.Ref[len("#/$defs/"):]
+		def, ok := defs[name]
+		if !ok {
+			return fmt.Errorf("unknown ref %s", s.Ref)
+		}
+		return validate(defs, def, v)
+	}
+	if s.OneOf != nil {
+		matched := 0
+		for _, option := range s.OneOf {
+			if validate(defs, option, v) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%v matched %d options instead of one", v, matched)
+		}
+		return nil
+	}
+	if s.Const != nil {
+		want, _ := json.Marshal(s.Const)
+		got, _ := json.Marshal(v)
+		if !bytes.Equal(want, got) {
+			return fmt.Errorf("%s is not %s", got, want)
+		}
+	}
+	if s.Enum != nil {
+		str, _ := v.(string)
+		found := false
+		for _, e := range s.Enum {
+			found = found || e == str
+		}
+		if !found {
+			return fmt.Errorf("%v is not one of %v", v, s.Enum)
+		}
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+	case "null":
+		if v != nil {
+			return fmt.Errorf("%v is not null", v)
+		}
+	case "boolean", "string":
+		want := map[string]reflect.Kind{"boolean": reflect.Bool, "string": reflect.String}[s.Type]
+		if v == nil || reflect.TypeOf(v).Kind() != want {
+			return fmt.Errorf("%v is not a %s", v, s.Type)
+		}
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok || (s.Type == "integer" && n != float64(int64(n))) {
+			return fmt.Errorf("%v is not an %s", v, s.Type)
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%v is not an array", v)
+		}
+		for i, item := range items {
+			if err := validate(defs, s.Items, item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%v is not an object", v)
+		}
+		for _, r := range s.Required {
+			if _, ok := obj[r]; !ok {
+				return fmt.Errorf("missing required property %q", r)
+			}
+		}
+		for k, value := range obj {
+			property, ok := s.Properties[k]
+			if !ok {
+				switch ap := s.AdditionalProperties.(type) {
+				case map[string]interface{}:
+					// round-trip so additionalProperties can be used as a schema
+					b, _ := json.Marshal(ap)
+					property = &Schema{}
+					json.Unmarshal(b, property)
+				default:
+					return fmt.Errorf("unexpected property %q", k)
+				}
+			}
+			if err := validate(defs, property, value); err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown type %s", s.Type)
+	}
+	return nil
+}
+
+func TestMessagesMatchSchema(t *testing.T) {
+	raw, err := os.ReadFile(schemaFile)
+	assert.Nil(t, err)
+	root := &Schema{}
+	assert.Nil(t, json.Unmarshal(raw, root))
+
+	seer := player.NewPlayer(player.NewMockCommunicator())
+	seer.SetRole(role.Seer())
+	wolf := player.NewPlayer(player.NewMockCommunicator())
+	wolf.SetRole(role.Werewolf())
+	players := []*player.Player{seer, wolf}
+	tl := tally.New(players)
+	tl.Vote(&player.FingerPoint{From: seer, To: wolf})
+
+	messages := map[server.MessageType]interface{}{
+		server.Awoo:            "awooooooooo",
+		server.IDSet:           seer.ID,
+		server.NameSet:         seer.Name,
+		server.PlayerJoin:      seer,
+		server.PlayerLeave:     wolf,
+		server.AlivePlayerList: players,
+		server.RolesetList:     roleset.List(),
+		server.RolesetSelected: roleset.Fiver(),
+		server.LeaderSet:       nil,
+		server.Password:        "correct horse battery",
+		server.TallyChanged:    tl,
+		server.RoleAssigned:    seer.Role,
+		server.PhaseChanged:    &server.Phase{Phase: server.Night, Count: 2},
+		server.View:            player.NewAttributeView(wolf, role.MaxEvilAttribute, true, 2),
+		server.PlayerKilled:    nil,
+		server.GameOver: &server.GameOverMessage{
+			Winner:  role.Good,
+			Winners: []uuid.UUID{seer.ID},
+			Roles:   []*server.RevealedPlayer{seer.Reveal(), wolf.Reveal()},
+		},
+		server.Error:             server.NewErrorMessage("r1", &client.DecodeError{Kind: client.InvalidPayload}),
+		server.Ack:               &server.Acknowledgement{RequestID: "r1", MessageType: client.Vote},
+		server.GameReset:         nil,
+		server.Kicked:            nil,
+		server.PlayerSubstituted: wolf,
+		server.SessionToken:      "c2VjcmV0",
+		server.LynchRuleSet:      &server.LynchRule{Rule: "pluralityAtDeadline", DayLength: 300},
+		server.RevengeShot:       &server.RevengeShotPrompt{Hunter: seer.ID, Deadline: time.Unix(30, 0).UTC()},
+		server.RevealModeSet:     "team",
+		server.VoteHistory: []*tally.Day{
+			{Phase: 1, Log: []*tally.Change{
+				{Voter: seer, To: wolf, Timestamp: time.Unix(0, 0).UTC()},
+				{Voter: seer, From: wolf, Timestamp: time.Unix(60, 0).UTC()},
+			}},
+		},
+		server.Timeline: []*server.TimelineEvent{
+			{Type: server.VoteCast, Phase: 1, Time: time.Unix(0, 0).UTC(), From: seer.ID, To: wolf.ID},
+			{Type: server.ViewResult, Phase: 2, Time: time.Unix(0, 0).UTC(), From: seer.ID, To: wolf.ID, Attribute: role.MaxEvilAttribute, Hit: true},
+			{Type: server.Death, Phase: 2, Time: time.Unix(0, 0).UTC(), To: seer.ID, Cause: server.NightKilled},
+			{Type: server.HunterShot, Phase: 2, Time: time.Unix(0, 0).UTC(), From: seer.ID, To: wolf.ID},
+		},
+	}
+	assert.Len(t, messages, len(ServerPayloads))
+	for mt, payload := range messages {
+		t.Run(string(mt), func(t *testing.T) {
+			b, err := server.NewMessage(mt, payload)
+			assert.Nil(t, err)
+			var v interface{}
+			assert.Nil(t, json.Unmarshal(b, &v))
+			assert.Nil(t, validate(root.Defs, root, v))
+		})
+	}
+
+	t.Run("role view", func(t *testing.T) {
+		b, err := server.NewMessage(server.View, player.NewRoleView(seer, seer.Role, 1))
+		assert.Nil(t, err)
+		var v interface{}
+		assert.Nil(t, json.Unmarshal(b, &v))
+		assert.Nil(t, validate(root.Defs, root, v))
+	})
+
+	t.Run("team view", func(t *testing.T) {
+		b, err := server.NewMessage(server.View, player.NewTeamView(wolf, role.Evil, 1))
+		assert.Nil(t, err)
+		var v interface{}
+		assert.Nil(t, json.Unmarshal(b, &v))
+		assert.Nil(t, validate(root.Defs, root, v))
+	})
+
+	target := uuid.New().String()
+	for _, raw := range []string{
+		`{"version":1,"requestId":"a","messageType":"awoo"}`,
+		`{"version":1,"messageType":"setName","payload":{"playerName":"Dake"}}`,
+		`{"version":1,"messageType":"setRoleset","payload":{"roleset":"Fast Fiver"}}`,
+		`{"version":1,"messageType":"vote","payload":{"target":"` + target + `"}}`,
+		`{"version":1,"messageType":"nightAction","payload":{"target":"` + target + `"}}`,
+		`{"version":1,"messageType":"start"}`,
+		`{"version":1,"messageType":"setLynchRule","payload":{"rule":"supermajority"}}`,
+		`{"version":1,"messageType":"quit"}`,
+	} {
+		var v interface{}
+		assert.Nil(t, json.Unmarshal([]byte(raw), &v))
+		assert.Nil(t, validate(root.Defs, root, v), raw)
+		_, err := client.Decode([]byte(raw))
+		assert.Nil(t, err, raw)
+	}
+
+	t.Run("divergence is caught", func(t *testing.T) {
+		b := []byte(`{"messageType":"phaseChanged","payload":{"phase":"day","count":1,"extra":true}}`)
+		var v interface{}
+		assert.Nil(t, json.Unmarshal(b, &v))
+		assert.Error(t, validate(root.Defs, root, v))
+	})
+}
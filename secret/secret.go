@@ -0,0 +1,18 @@
+// Package secret makes the random strings used for session tokens and
+// account keys.
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// New returns n random bytes, encoded to be safe in URLs.
+func New(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand doesn't fail on supported platforms
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,16 @@
+package secret
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	a, b := New(32), New(32)
+	assert.NotEqual(t, a, b)
+	raw, err := base64.RawURLEncoding.DecodeString(a)
+	assert.Nil(t, err)
+	assert.Len(t, raw, 32)
+}
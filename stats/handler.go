@@ -0,0 +1,42 @@
+package stats
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/awoo-detat/werewolf/game"
+)
+
+// RolesetParam limits the stats served to games played with one roleset.
+const RolesetParam = "roleset"
+
+// A Source has the results of finished games, oldest first, as an
+// account.Store does.
+type Source interface {
+	Results() []*game.Result
+}
+
+// A Handler serves the Stats of the games in its Source as JSON.
+type Handler struct {
+	Source Source
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	results := h.Source.Results()
+	if rs := r.URL.Query().Get(RolesetParam); rs != "" {
+		filtered := []*game.Result{}
+		for _, res := range results {
+			if res.Roleset == rs {
+				filtered = append(filtered, res)
+			}
+		}
+		results = filtered
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Compute(results))
+}
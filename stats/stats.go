@@ -0,0 +1,206 @@
+// Package stats summarises the results of finished games: how often each role
+// and team wins, how well seers see, how long wolves last, and a rating for
+// each player with an account.
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"github.com/awoo-detat/werewolf/game"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// InitialRating is a player's rating before their first game.
+	InitialRating = 1500.0
+	// KFactor is the most a rating can move in one game.
+	KFactor = 32.0
+)
+
+// A Record counts games played and won.
+type Record struct {
+	Games int `json:"games"`
+	Wins  int `json:"wins"`
+}
+
+func (r *Record) add(won bool) {
+	r.Games++
+	if won {
+		r.Wins++
+	}
+}
+
+// WinRate is the share of games won, or 0 if none were played.
+func (r *Record) WinRate() float64 {
+	return rate(r.Wins, r.Games)
+}
+
+func rate(n, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return float64(n) / float64(of)
+}
+
+// A RoleStats is how a role has done. Checks and Hits count its night views,
+// so HitRate is only meaningful for seers.
+type RoleStats struct {
+	Record
+	WinRate float64 `json:"winRate"`
+	Checks  int     `json:"checks"`
+	Hits    int     `json:"hits"`
+	HitRate float64 `json:"hitRate"`
+}
+
+type TeamStats struct {
+	Record
+	WinRate float64 `json:"winRate"`
+}
+
+// A PlayerStats is a leaderboard entry for one account.
+type PlayerStats struct {
+	AccountID uuid.UUID `json:"accountId"`
+	Name      string    `json:"name"`
+	Record
+	WinRate float64 `json:"winRate"`
+	Rating  float64 `json:"rating"`
+}
+
+// Stats summarises a set of games. Wolves counts max evils, and
+// WolfSurvival is the share of them still alive when their game ended.
+// Leaderboard is ordered by rating, highest first.
+type Stats struct {
+	Games        int                   `json:"games"`
+	Roles        map[string]*RoleStats `json:"roles"`
+	Teams        map[string]*TeamStats `json:"teams"`
+	SeerHitRate  float64               `json:"seerHitRate"`
+	Wolves       int                   `json:"wolves"`
+	WolfSurvival float64               `json:"wolfSurvival"`
+	Leaderboard  []*PlayerStats        `json:"leaderboard"`
+}
+
+// Compute summarises results, which should be oldest first: ratings depend on
+// the order games were played in.
+func Compute(results []*game.Result) *Stats {
+	s := &Stats{
+		Games:       len(results),
+		Roles:       make(map[string]*RoleStats),
+		Teams:       make(map[string]*TeamStats),
+		Leaderboard: []*PlayerStats{},
+	}
+	players := make(map[uuid.UUID]*PlayerStats)
+	checks, hits, survivors := 0, 0, 0
+
+	for _, r := range results {
+		for _, p := range r.Players {
+			rs, ok := s.Roles[p.Role]
+			if !ok {
+				rs = &RoleStats{}
+				s.Roles[p.Role] = rs
+			}
+			rs.add(p.Won)
+			rs.Checks += p.Checks
+			rs.Hits += p.Hits
+
+			ts, ok := s.Teams[p.Team.String()]
+			if !ok {
+				ts = &TeamStats{}
+				s.Teams[p.Team.String()] = ts
+			}
+			ts.add(p.Won)
+
+			if p.Role == role.Seer().Name {
+				checks += p.Checks
+				hits += p.Hits
+			}
+			if p.MaxEvil {
+				s.Wolves++
+				if p.Alive {
+					survivors++
+				}
+			}
+
+			if p.AccountID == uuid.Nil {
+				continue
+			}
+			ps, ok := players[p.AccountID]
+			if !ok {
+				ps = &PlayerStats{AccountID: p.AccountID, Rating: InitialRating}
+				players[p.AccountID] = ps
+			}
+			// the name they last played under
+			ps.Name = p.Name
+			ps.add(p.Won)
+		}
+		updateRatings(players, r)
+	}
+
+	for _, rs := range s.Roles {
+		rs.WinRate = rs.Record.WinRate()
+		rs.HitRate = rate(rs.Hits, rs.Checks)
+	}
+	for _, ts := range s.Teams {
+		ts.WinRate = ts.Record.WinRate()
+	}
+	s.SeerHitRate = rate(hits, checks)
+	s.WolfSurvival = rate(survivors, s.Wolves)
+	for _, ps := range players {
+		ps.WinRate = ps.Record.WinRate()
+		s.Leaderboard = append(s.Leaderboard, ps)
+	}
+	sort.Slice(s.Leaderboard, func(i, j int) bool {
+		if s.Leaderboard[i].Rating != s.Leaderboard[j].Rating {
+			return s.Leaderboard[i].Rating > s.Leaderboard[j].Rating
+		}
+		return s.Leaderboard[i].Name < s.Leaderboard[j].Name
+	})
+	return s
+}
+
+// updateRatings moves the ratings of the account holders in a game, Elo
+// style, treating it as a match between the winners and everyone else. Each
+// side is rated as the average of its players, with guests at InitialRating,
+// so an upset moves ratings further than an expected win.
+func updateRatings(players map[uuid.UUID]*PlayerStats, r *game.Result) {
+	var winners, losers []float64
+	for _, p := range r.Players {
+		rating := InitialRating
+		if ps, ok := players[p.AccountID]; ok {
+			rating = ps.Rating
+		}
+		if p.Won {
+			winners = append(winners, rating)
+		} else {
+			losers = append(losers, rating)
+		}
+	}
+	if len(winners) == 0 || len(losers) == 0 {
+		return
+	}
+	w, l := mean(winners), mean(losers)
+	expected := 1 / (1 + math.Pow(10, (l-w)/400))
+	// the winners gain what the losers lose
+	delta := KFactor * (1 - expected)
+	for _, p := range r.Players {
+		ps, ok := players[p.AccountID]
+		if !ok {
+			continue
+		}
+		if p.Won {
+			ps.Rating += delta
+		} else {
+			ps.Rating -= delta
+		}
+	}
+}
+
+func mean(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awoo-detat/werewolf/game"
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type results []*game.Result
+
+func (r results) Results() []*game.Result { return r }
+
+// fiver is a Vanilla Fiver result: alice the seer, bob the wolf.
+func fiver(alice, bob uuid.UUID, winner role.PlayerType) *game.Result {
+	goodWon := winner == role.Good
+	r := &game.Result{ID: uuid.New(), Roleset: "Vanilla Fiver", Winner: winner, Players: []*game.PlayerResult{
+		{AccountID: alice, Name: "alice", Role: "Seer", Team: role.Good, Won: goodWon, Checks: 2, Hits: 1},
+		{AccountID: bob, Name: "bob", Role: "Werewolf", Team: role.Evil, MaxEvil: true, Won: !goodWon, Alive: !goodWon},
+	}}
+	for i := 0; i < 3; i++ {
+		r.Players = append(r.Players, &game.PlayerResult{Role: "Villager", Team: role.Good, Won: goodWon, Alive: true})
+	}
+	return r
+}
+
+func TestCompute(t *testing.T) {
+	assert := assert.New(t)
+	alice, bob := uuid.New(), uuid.New()
+	s := Compute([]*game.Result{
+		fiver(alice, bob, role.Good),
+		fiver(alice, bob, role.Good),
+		fiver(alice, bob, role.Evil),
+	})
+
+	assert.Equal(3, s.Games)
+	assert.Equal(Record{Games: 3, Wins: 2}, s.Roles["Seer"].Record)
+	assert.InDelta(2.0/3, s.Roles["Seer"].WinRate, 0.001)
+	assert.InDelta(1.0/3, s.Teams["Evil"].WinRate, 0.001)
+	assert.Equal(0.5, s.SeerHitRate)
+	assert.Equal(3, s.Wolves)
+	assert.InDelta(1.0/3, s.WolfSurvival, 0.001)
+
+	require.Len(t, s.Leaderboard, 2)
+	assert.Equal(alice, s.Leaderboard[0].AccountID)
+	assert.Greater(s.Leaderboard[0].Rating, InitialRating)
+	assert.Less(s.Leaderboard[1].Rating, InitialRating)
+	// what one gains the other loses
+	assert.InDelta(2*InitialRating, s.Leaderboard[0].Rating+s.Leaderboard[1].Rating, 0.001)
+}
+
+func TestUpsetMovesRatingsFurther(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	s := Compute([]*game.Result{fiver(alice, bob, role.Good)})
+	gain := s.Leaderboard[0].Rating - InitialRating
+
+	// bob is now the underdog, so his win is worth more than alice's was
+	s = Compute([]*game.Result{fiver(alice, bob, role.Good), fiver(alice, bob, role.Evil)})
+	for _, ps := range s.Leaderboard {
+		if ps.AccountID == bob {
+			assert.Greater(t, ps.Rating-(InitialRating-gain), gain)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	other := fiver(alice, bob, role.Evil)
+	other.Roleset = "Something Else"
+	h := &Handler{Source: results{fiver(alice, bob, role.Good), other}}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats?roleset=Vanilla+Fiver", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var s Stats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &s))
+	assert.Equal(t, 1, s.Games)
+	assert.Equal(t, 1.0, s.Teams["Good"].WinRate)
+}
@@ -0,0 +1,195 @@
+package tally
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/awoo-detat/werewolf/player"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clock returns a fake clock that moves on by step each time it is read.
+func clock(step time.Duration) func() time.Time {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+// An op is a vote, or an unvote if to is -1.
+type op struct {
+	from, to int
+}
+
+func (o op) String() string {
+	if o.to < 0 {
+		return fmt.Sprintf("%d unvotes", o.from)
+	}
+	return fmt.Sprintf("%d->%d", o.from, o.to)
+}
+
+// expectedOrder works out what order the tally should be in after ops, from
+// scratch: most votes first, then the earliest last vote, then by name.
+func expectedOrder(players []*player.Player, ops []op, times []time.Time) []*player.Player {
+	current := make(map[int]int)
+	cast := make(map[int]time.Time)
+	for i, o := range ops {
+		if o.to < 0 {
+			delete(current, o.from)
+			continue
+		}
+		current[o.from] = o.to
+		cast[o.from] = times[i]
+	}
+	votes := make([]int, len(players))
+	last := make([]time.Time, len(players))
+	for voter, candidate := range current {
+		votes[candidate]++
+		if cast[voter].After(last[candidate]) {
+			last[candidate] = cast[voter]
+		}
+	}
+
+	order := []int{}
+	for i := range players {
+		order = append(order, i)
+	}
+	sort.Slice(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if votes[i] != votes[j] {
+			return votes[i] > votes[j]
+		}
+		if !last[i].Equal(last[j]) {
+			return last[i].Before(last[j])
+		}
+		return players[i].Name < players[j].Name
+	})
+	expected := []*player.Player{}
+	for _, i := range order {
+		expected = append(expected, players[i])
+	}
+	return expected
+}
+
+// sequences calls f with every sequence of up to n ops.
+func sequences(ops []op, n int, f func([]op)) {
+	var walk func(prefix []op)
+	walk = func(prefix []op) {
+		if len(prefix) > 0 {
+			f(prefix)
+		}
+		if len(prefix) == n {
+			return
+		}
+		for _, o := range ops {
+			walk(append(prefix[:len(prefix):len(prefix)], o))
+		}
+	}
+	walk(nil)
+}
+
+func TestLHLVExhaustive(t *testing.T) {
+	players := []*player.Player{}
+	for _, name := range []string{"Ash", "Birch", "Cedar"} {
+		p := player.NewPlayer(player.NewMockCommunicator())
+		p.SetName(name)
+		players = append(players, p)
+	}
+	ops := []op{}
+	for from := range players {
+		ops = append(ops, op{from, -1})
+		for to := range players {
+			ops = append(ops, op{from, to})
+		}
+	}
+
+	// a clock that never moves makes every vote a tie on time
+	for _, step := range []time.Duration{time.Second, 0} {
+		t.Run(step.String(), func(t *testing.T) {
+			sequences(ops, 4, func(seq []op) {
+				gt := New(append([]*player.Player{}, players...))
+				gt.Now = clock(step)
+				times := []time.Time{}
+				for _, o := range seq {
+					if o.to < 0 {
+						gt.Unvote(players[o.from])
+						times = append(times, time.Time{})
+						continue
+					}
+					gt.Vote(&player.FingerPoint{From: players[o.from], To: players[o.to]})
+					times = append(times, gt.Inverted[players[o.from]].Timestamp)
+				}
+
+				got := []*player.Player{}
+				total := 0
+				for _, item := range gt.List {
+					got = append(got, item.Player)
+					total += len(item.Votes)
+					for _, v := range item.Votes {
+						require.Same(t, item.Player, v.Candidate, "%v", seq)
+						require.Same(t, v, gt.Inverted[v.Voter], "%v", seq)
+					}
+				}
+				cast := 0
+				for _, v := range gt.Inverted {
+					if v != nil {
+						cast++
+					}
+				}
+				require.Equal(t, cast, total, "%v", seq)
+				require.Equal(t, expectedOrder(players, seq, times), got, "%v", seq)
+			})
+		})
+	}
+}
+
+func TestLHLVAfterUnvote(t *testing.T) {
+	assert := assert.New(t)
+	a := player.NewPlayer(player.NewMockCommunicator())
+	a.SetName("A")
+	b := player.NewPlayer(player.NewMockCommunicator())
+	b.SetName("B")
+	c := player.NewPlayer(player.NewMockCommunicator())
+	c.SetName("C")
+	gt := New([]*player.Player{a, b, c})
+	gt.Now = clock(time.Second)
+
+	// B is voted for first, but A's vote has been held longer once B's
+	// first voter moves and comes back
+	gt.Vote(&player.FingerPoint{From: a, To: b})
+	gt.Vote(&player.FingerPoint{From: b, To: c})
+	gt.Unvote(a)
+	gt.Vote(&player.FingerPoint{From: a, To: b})
+	assert.Equal(c, gt.List[0].Player)
+	assert.Equal(b, gt.List[1].Player)
+	assert.Equal(a, gt.List[2].Player)
+}
+
+func TestRevoteKeepsPlace(t *testing.T) {
+	assert := assert.New(t)
+	a := player.NewPlayer(player.NewMockCommunicator())
+	a.SetName("A")
+	b := player.NewPlayer(player.NewMockCommunicator())
+	b.SetName("B")
+	c := player.NewPlayer(player.NewMockCommunicator())
+	c.SetName("C")
+	gt := New([]*player.Player{a, b, c})
+	gt.Now = clock(time.Second)
+
+	assert.True(gt.Vote(&player.FingerPoint{From: a, To: b}))
+	assert.True(gt.Vote(&player.FingerPoint{From: b, To: c}))
+	held := gt.Inverted[a]
+	changes := len(gt.Log)
+
+	// voting for B again doesn't make A's vote any newer
+	assert.False(gt.Vote(&player.FingerPoint{From: a, To: b}))
+	assert.Same(held, gt.Inverted[a])
+	assert.Len(gt.Log, changes)
+	assert.Equal(b, gt.List[0].Player)
+	assert.Equal(c, gt.List[1].Player)
+}
@@ -0,0 +1,27 @@
+package tally
+
+import (
+	"time"
+
+	"github.com/awoo-detat/werewolf/player"
+)
+
+// A Change is one entry in a tally's log: Voter's vote moving From one
+// candidate To another. From is nil for a first vote, and To is nil when a
+// vote is withdrawn.
+type Change struct {
+	Voter     *player.Player `json:"voter"`
+	From      *player.Player `json:"from"`
+	To        *player.Player `json:"to"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// A Day is the log of one day's tally, kept once the day is over.
+type Day struct {
+	Phase int       `json:"phase"`
+	Log   []*Change `json:"log"`
+}
+
+func (t *Tally) logChange(voter, from, to *player.Player, at time.Time) {
+	t.Log = append(t.Log, &Change{Voter: voter, From: from, To: to, Timestamp: at})
+}
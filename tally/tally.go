@@ -2,7 +2,9 @@ package tally
 
 import (
 	"log/slog"
+	"slices"
 	"sort"
+	"time"
 
 	"github.com/awoo-detat/werewolf/player"
 	"github.com/awoo-detat/werewolf/vote"
@@ -10,50 +12,66 @@ import (
 
 // A Tally is a list of players and the votes they have received.
 // It is in descending order by number of votes and by longest
-// held last vote (LHLV).
+// held last vote (LHLV): among players with as many votes, the one
+// whose most recent vote was cast earliest leads.
 type Tally struct {
 	// Item is a map of votes ordered by the person being voted for.
 	List []*TallyItem `json:"list"`
+	// Log is every vote and unvote made on this tally, in order.
+	Log []*Change `json:"log"`
+	// VotesNeeded is how many votes it takes to be lynched, or 0 if nobody
+	// is lynched until Deadline.
+	VotesNeeded int        `json:"votesNeeded"`
+	Deadline    *time.Time `json:"deadline,omitempty"`
 	// Inverted is a map ordered by the player doing the voting.
-	voteMap     map[*player.Player]*TallyItem
-	Inverted    map[*player.Player]*vote.Vote `json:"-"`
+	voteMap  map[*player.Player]*TallyItem
+	Inverted map[*player.Player]*vote.Vote `json:"-"`
+	// Now is the clock votes are timestamped with.
+	Now         func() time.Time `json:"-"`
 	playerCount int
 }
 
 func New(players []*player.Player) *Tally {
 	t := &Tally{
 		List:        []*TallyItem{},
+		Log:         []*Change{},
 		voteMap:     make(map[*player.Player]*TallyItem),
 		Inverted:    make(map[*player.Player]*vote.Vote),
+		Now:         time.Now,
 		playerCount: len(players),
 	}
-	sort.Slice(players, func(i, j int) bool {
-		return players[i].Name < players[j].Name
-	})
 	for _, p := range players {
 		ti := NewTallyItem(p)
 		t.List = append(t.List, ti)
 		t.voteMap[p] = ti
 		t.Inverted[p] = nil
 	}
+	t.sort()
 	return t
 }
 
-func (t *Tally) Vote(fp *player.FingerPoint) {
+// Vote records fp.From's vote for fp.To, replacing any vote they had. It
+// reports whether anything changed: voting again for the same candidate
+// doesn't, so it can't be used to look like a fresh vote.
+func (t *Tally) Vote(fp *player.FingerPoint) bool {
 	slog.Info("vote received", "fingerpoint", fp)
 	// if they've voted for anyone before, remove it from the tally
+	var previous *player.Player
 	if current := t.Inverted[fp.From]; current != nil {
+		if current.Candidate == fp.To {
+			return false
+		}
 		t.voteMap[current.Candidate].RemoveVote(current)
+		previous = current.Candidate
 	}
-	v := vote.New(fp)
+	v := vote.NewAt(fp, t.Now())
+	t.logChange(fp.From, previous, fp.To, v.Timestamp)
 	// add to the tally
 	t.voteMap[fp.To].AddVote(v)
 	// update the inverted tally
 	t.Inverted[fp.From] = v
-
-	sort.Slice(t.List, func(i, j int) bool {
-		return len(t.List[i].Votes) > len(t.List[j].Votes)
-	})
+	t.sort()
+	return true
 }
 
 func (t *Tally) Unvote(from *player.Player) {
@@ -64,4 +82,32 @@ func (t *Tally) Unvote(from *player.Player) {
 	}
 	t.voteMap[v.Candidate].RemoveVote(v)
 	t.Inverted[from] = nil
+	t.logChange(from, v.Candidate, nil, t.Now())
+	t.sort()
+}
+
+// Remove takes a player off the tally, along with their vote and every vote
+// for them, as when they leave the game.
+func (t *Tally) Remove(p *player.Player) {
+	slog.Info("removing player from tally", "player", p)
+	t.Unvote(p)
+	ti, ok := t.voteMap[p]
+	if !ok {
+		return
+	}
+	for _, v := range ti.Votes {
+		t.Inverted[v.Voter] = nil
+		t.logChange(v.Voter, p, nil, t.Now())
+	}
+	delete(t.voteMap, p)
+	delete(t.Inverted, p)
+	t.List = slices.DeleteFunc(t.List, func(i *TallyItem) bool { return i == ti })
+	t.playerCount--
+	t.sort()
+}
+
+// sort puts the tally in order. The order is total, so it doesn't depend on
+// the order the list was in before.
+func (t *Tally) sort() {
+	sort.Slice(t.List, func(i, j int) bool { return t.List[i].ahead(t.List[j]) })
 }
@@ -2,6 +2,7 @@ package tally
 
 import (
 	"testing"
+	"time"
 
 	"github.com/awoo-detat/werewolf/player"
 
@@ -19,6 +20,7 @@ func TestTally(t *testing.T) {
 	sigafoos.SetName("Sigafoos")
 	players := []*player.Player{dake, tommy, sigafoos}
 	gt := New(players)
+	gt.Now = clock(time.Second)
 
 	t.Run("Creation", func(t *testing.T) {
 		t.Run("Sorted by name", func(t *testing.T) {
@@ -101,3 +103,66 @@ func TestTally(t *testing.T) {
 		assert.Empty(gt.List[2].Votes)
 	})
 }
+
+func TestRemove(t *testing.T) {
+	assert := assert.New(t)
+	dake := player.NewPlayer(player.NewMockCommunicator())
+	dake.SetName("Dake")
+	tommy := player.NewPlayer(player.NewMockCommunicator())
+	tommy.SetName("Tommy")
+	sigafoos := player.NewPlayer(player.NewMockCommunicator())
+	sigafoos.SetName("Sigafoos")
+	gt := New([]*player.Player{dake, tommy, sigafoos})
+
+	gt.Vote(&player.FingerPoint{From: dake, To: tommy})
+	gt.Vote(&player.FingerPoint{From: sigafoos, To: tommy})
+	gt.Vote(&player.FingerPoint{From: tommy, To: dake})
+
+	gt.Remove(tommy)
+
+	assert.Len(gt.List, 2)
+	assert.Equal(dake, gt.List[0].Player, "with no votes left, the tally is in name order")
+	assert.Empty(gt.List[0].Votes)
+	assert.Empty(gt.List[1].Votes)
+	assert.Nil(gt.Inverted[dake], "votes for tommy are withdrawn")
+	assert.Nil(gt.Inverted[sigafoos])
+	assert.NotContains(gt.Inverted, tommy)
+
+	// the voters can vote again
+	gt.Vote(&player.FingerPoint{From: sigafoos, To: dake})
+	assert.Len(gt.List[0].Votes, 1)
+}
+
+func TestLog(t *testing.T) {
+	assert := assert.New(t)
+	dake := player.NewPlayer(player.NewMockCommunicator())
+	tommy := player.NewPlayer(player.NewMockCommunicator())
+	sigafoos := player.NewPlayer(player.NewMockCommunicator())
+	gt := New([]*player.Player{dake, tommy, sigafoos})
+
+	gt.Vote(&player.FingerPoint{From: dake, To: tommy})
+	gt.Vote(&player.FingerPoint{From: dake, To: sigafoos})
+	gt.Unvote(dake)
+	gt.Unvote(dake)
+	gt.Vote(&player.FingerPoint{From: sigafoos, To: tommy})
+	gt.Remove(tommy)
+
+	expected := []Change{
+		{Voter: dake, To: tommy},
+		{Voter: dake, From: tommy, To: sigafoos},
+		{Voter: dake, From: sigafoos},
+		{Voter: sigafoos, To: tommy},
+		{Voter: sigafoos, From: tommy},
+	}
+	if assert.Len(gt.Log, len(expected)) {
+		for i, c := range gt.Log {
+			assert.Equal(expected[i].Voter, c.Voter, i)
+			assert.Equal(expected[i].From, c.From, i)
+			assert.Equal(expected[i].To, c.To, i)
+			assert.False(c.Timestamp.IsZero())
+			if i > 0 {
+				assert.False(c.Timestamp.Before(gt.Log[i-1].Timestamp))
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package tally
 
 import (
 	"slices"
+	"time"
 
 	"github.com/awoo-detat/werewolf/player"
 	"github.com/awoo-detat/werewolf/vote"
@@ -30,3 +31,33 @@ func (i *TallyItem) RemoveVote(v *vote.Vote) {
 func (i *TallyItem) AddVote(v *vote.Vote) {
 	i.Votes = append(i.Votes, v)
 }
+
+// LastVote is when the item's most recent vote was cast, or the zero time if
+// it has none.
+func (i *TallyItem) LastVote() time.Time {
+	last := time.Time{}
+	for _, v := range i.Votes {
+		if v.Timestamp.After(last) {
+			last = v.Timestamp
+		}
+	}
+	return last
+}
+
+// ahead reports whether i belongs above j on the tally: it has more votes,
+// or as many and its last vote is the longest held (LHLV). Anything still
+// tied, including players with no votes, is in name order.
+func (i *TallyItem) ahead(j *TallyItem) bool {
+	if len(i.Votes) != len(j.Votes) {
+		return len(i.Votes) > len(j.Votes)
+	}
+	if len(i.Votes) > 0 {
+		if li, lj := i.LastVote(), j.LastVote(); !li.Equal(lj) {
+			return li.Before(lj)
+		}
+	}
+	if i.Player.Name != j.Player.Name {
+		return i.Player.Name < j.Player.Name
+	}
+	return i.Player.ID.String() < j.Player.ID.String()
+}
@@ -13,9 +13,14 @@ type Vote struct {
 }
 
 func New(fp *player.FingerPoint) *Vote {
+	return NewAt(fp, time.Now())
+}
+
+// NewAt makes a vote cast at the given time.
+func NewAt(fp *player.FingerPoint, at time.Time) *Vote {
 	return &Vote{
 		Candidate: fp.To,
 		Voter:     fp.From,
-		Timestamp: time.Now(),
+		Timestamp: at,
 	}
 }
@@ -0,0 +1,135 @@
+// Package win decides when a game is over, and which team has won.
+package win
+
+import (
+	"github.com/awoo-detat/werewolf/role"
+)
+
+// A Condition is checked after every death and phase change, given the roles
+// of the players still alive. It reports whether the game is over and, if
+// so, which team won. When role.Neutral wins, the winners are those of the
+// living given by NeutralWinners.
+//
+// Conditions compose: Lovers and Loners wrap another condition, which decides
+// the game when their own rule doesn't.
+type Condition interface {
+	Check(alive []*role.Role) (winner role.PlayerType, over bool)
+}
+
+// count splits the living into max evils and everyone else, and adds up
+// their parity.
+func count(alive []*role.Role) (maxes, nonmaxes, parity int) {
+	for _, r := range alive {
+		if r.IsMaxEvil() {
+			maxes++
+		} else {
+			nonmaxes++
+		}
+		parity += r.Parity
+	}
+	return
+}
+
+// Parity is the usual rule, and the default: good wins once the max evils
+// are dead, and evil once they have parity, unless a Hunter holds them off.
+type Parity struct{}
+
+func (Parity) Check(alive []*role.Role) (role.PlayerType, bool) {
+	maxes, nonmaxes, parity := count(alive)
+	equality := maxes == nonmaxes
+	switch {
+	case maxes == 0:
+		return role.Good, true
+	case parity < 0:
+		return role.Evil, true
+	case parity == 0 && equality:
+		return role.Evil, true
+	case equality:
+		// due to a hunter, evil loses
+		// TODO: what will/should happen with ancient WW vs hunter?
+		return role.Good, true
+	}
+	return 0, false
+}
+
+// Majority lets evil win only once the max evils outnumber everyone else.
+// Good wins once the max evils are dead.
+type Majority struct{}
+
+func (Majority) Check(alive []*role.Role) (role.PlayerType, bool) {
+	maxes, nonmaxes, _ := count(alive)
+	switch {
+	case maxes == 0:
+		return role.Good, true
+	case maxes > nonmaxes:
+		return role.Evil, true
+	}
+	return 0, false
+}
+
+// AllEvilDead makes good hunt down the aux evils as well as the wolves.
+// Otherwise it is Parity.
+type AllEvilDead struct{}
+
+func (AllEvilDead) Check(alive []*role.Role) (role.PlayerType, bool) {
+	evil := 0
+	for _, r := range alive {
+		if r.Team == role.Evil {
+			evil++
+		}
+	}
+	if evil == 0 {
+		return role.Good, true
+	}
+	if maxes, _, _ := count(alive); maxes == 0 {
+		return 0, false
+	}
+	return Parity{}.Check(alive)
+}
+
+// Loners is for rolesets with roles that play alone, such as the Serial
+// Killer. Neither side wins while one is alive; once no more than two are
+// left, the loners win. Without any alive, Then decides.
+type Loners struct {
+	Then Condition
+}
+
+func (c Loners) Check(alive []*role.Role) (role.PlayerType, bool) {
+	for _, r := range alive {
+		if r.WinCondition == role.WinAlone {
+			return role.Neutral, len(alive) <= 2
+		}
+	}
+	return c.Then.Check(alive)
+}
+
+// Lovers is for rolesets with lovers. If the last two alive are lovers from
+// different teams, they win together; otherwise Then decides.
+type Lovers struct {
+	Then Condition
+}
+
+func (c Lovers) Check(alive []*role.Role) (role.PlayerType, bool) {
+	if LoversWin(alive) {
+		return role.Neutral, true
+	}
+	return c.Then.Check(alive)
+}
+
+// LoversWin reports whether the only two left alive are lovers from
+// different teams.
+func LoversWin(alive []*role.Role) bool {
+	return len(alive) == 2 && alive[0].IsLover() && alive[1].IsLover() && alive[0].Team != alive[1].Team
+}
+
+// NeutralWinners returns those of alive who share in a role.Neutral win:
+// anyone playing alone, and lovers.
+func NeutralWinners(alive []*role.Role) []*role.Role {
+	winners := []*role.Role{}
+	for _, r := range alive {
+		if r.WinCondition == role.WinAlone || r.IsLover() {
+			winners = append(winners, r)
+		}
+	}
+	return winners
+}
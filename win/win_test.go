@@ -0,0 +1,76 @@
+package win
+
+import (
+	"testing"
+
+	"github.com/awoo-detat/werewolf/role"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func roles(makers ...func() *role.Role) []*role.Role {
+	rs := []*role.Role{}
+	for _, m := range makers {
+		rs = append(rs, m())
+	}
+	return rs
+}
+
+func TestConditions(t *testing.T) {
+	ww, cult, vil, hunter, sk := role.Werewolf, role.Cultist, role.Villager, role.Hunter, role.SerialKiller
+	for _, tc := range []struct {
+		name      string
+		condition Condition
+		alive     []*role.Role
+		winner    role.PlayerType
+		over      bool
+	}{
+		{"parity: wolves dead", Parity{}, roles(cult, vil), role.Good, true},
+		{"parity: still going", Parity{}, roles(ww, vil, vil), 0, false},
+		{"parity: wolf at parity", Parity{}, roles(ww, vil), role.Evil, true},
+		{"parity: hunter holds", Parity{}, roles(ww, hunter), role.Good, true},
+		{"parity: two wolves", Parity{}, roles(ww, ww, vil, vil), role.Evil, true},
+		{"majority: parity isn't enough", Majority{}, roles(ww, vil), 0, false},
+		{"majority: outnumbered", Majority{}, roles(ww, ww, vil), role.Evil, true},
+		{"majority: wolves dead", Majority{}, roles(vil), role.Good, true},
+		{"all evil: cultist lives", AllEvilDead{}, roles(cult, vil, vil), 0, false},
+		{"all evil: all dead", AllEvilDead{}, roles(vil, vil), role.Good, true},
+		{"all evil: wolf at parity", AllEvilDead{}, roles(ww, vil), role.Evil, true},
+		{"loners: killer lives", Loners{Parity{}}, roles(sk, vil, vil), 0, false},
+		{"loners: last two", Loners{Parity{}}, roles(sk, ww), role.Neutral, true},
+		{"loners: killer dead", Loners{Parity{}}, roles(ww, vil), role.Evil, true},
+		{"lovers: no lovers", Lovers{Parity{}}, roles(ww, vil), role.Evil, true},
+	} {
+		winner, over := tc.condition.Check(tc.alive)
+		assert.Equal(t, tc.over, over, tc.name)
+		if tc.over {
+			assert.Equal(t, tc.winner, winner, tc.name)
+		}
+	}
+}
+
+func TestLoversWin(t *testing.T) {
+	wolf, villager, seer := role.Werewolf(), role.Villager(), role.Seer()
+	assert.False(t, LoversWin([]*role.Role{wolf, villager}))
+	wolf.SetLover()
+	villager.SetLover()
+	assert.True(t, LoversWin([]*role.Role{wolf, villager}))
+	assert.False(t, LoversWin([]*role.Role{wolf, villager, seer}))
+
+	seer.SetLover()
+	assert.False(t, LoversWin([]*role.Role{villager, seer}), "lovers on the same team just win with it")
+}
+
+func TestLoversCondition(t *testing.T) {
+	wolf, villager := role.Werewolf(), role.Villager()
+	wolf.SetLover()
+	villager.SetLover()
+	winner, over := Lovers{Parity{}}.Check([]*role.Role{wolf, villager})
+	assert.True(t, over)
+	assert.Equal(t, role.PlayerType(role.Neutral), winner)
+
+	// the lovers share the win, but a loner's rival doesn't
+	killer := role.SerialKiller()
+	assert.Equal(t, []*role.Role{wolf, villager}, NeutralWinners([]*role.Role{wolf, villager}))
+	assert.Equal(t, []*role.Role{killer}, NeutralWinners([]*role.Role{killer, role.Villager()}))
+}